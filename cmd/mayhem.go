@@ -2,23 +2,51 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
 	"github.com/enckse/mayhem/internal/backend"
 	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/durations"
 	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/hooks"
+	"github.com/enckse/mayhem/internal/reminders"
+	"github.com/enckse/mayhem/internal/reports"
+	"github.com/enckse/mayhem/internal/revisions"
+	"github.com/enckse/mayhem/internal/server"
 	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/templates"
 	"github.com/enckse/mayhem/internal/tui/ui"
 )
 
+const (
+	// hookTimeout is how long a hook may run unless configured
+	hookTimeout = 10 * time.Second
+	// commitDebounce is how long changes settle before they are committed (off the UI) unless configured
+	commitDebounce = time.Second
+	// remindLead is when reminders are sent (before a deadline) unless configured
+	remindLead = "1d, 1h"
+	// remindInterval is how often reminders are checked when watching unless configured
+	remindInterval = time.Minute
+	// historyLimit is how many revisions are listed unless requested
+	historyLimit = 20
+)
+
 var version string
 
 func main() {
@@ -29,81 +57,800 @@ func main() {
 }
 
 func run() error {
-	args := os.Args
-	var configFile string
-	if len(args) > 1 {
+	args := os.Args[1:]
+	command := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
 		args = args[1:]
-		cmd := args[0]
-		if !strings.HasPrefix(cmd, "-") {
-			switch cmd {
-			case "version":
-				fmt.Fprintf(os.Stderr, "%s\n", version)
-				return nil
-			}
-			if len(args) > 1 {
-				args = args[1:]
-			}
+	}
+	switch command {
+	case "version":
+		fmt.Fprintf(os.Stderr, "%s\n", version)
+		return nil
+	case "report":
+		return report(args)
+	case "serve":
+		return serve(args)
+	case "remind":
+		return remind(args)
+	case "history":
+		return history(args)
+	case "restore":
+		return restore(args)
+	case "backup":
+		return backup(args)
+	case "encrypt":
+		return convert(args, true)
+	case "decrypt":
+		return convert(args, false)
+	case "profiles":
+		return profiles(args)
+	case "template":
+		return template(args)
+	case "":
+		return interactive(args)
+	}
+	return fmt.Errorf("unknown command: %s", command)
+}
+
+// configSource is where the configuration is loaded from (the file and profile)
+type configSource struct {
+	file    string
+	profile string
+}
+
+func (c configSource) load() (state.Config, error) {
+	return state.LoadProfile(c.file, c.profile)
+}
+
+// loadConfig will load the configuration, unlocking the data files when encrypted
+func loadConfig(source configSource) (state.Config, error) {
+	cfg, err := source.load()
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Unlock(promptKey)
+}
+
+// promptKey will prompt for the key (without echo) when there is a terminal
+func promptKey() (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", errors.New("no key available (not a terminal)")
+	}
+	fmt.Fprint(os.Stderr, "key: ")
+	b, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	return string(b), err
+}
+
+func newFlags(name string) (*flag.FlagSet, *configSource) {
+	set := flag.NewFlagSet(name, flag.ExitOnError)
+	source := &configSource{}
+	set.StringVar(&source.file, "config", "", "configuration file")
+	set.StringVar(&source.profile, "profile", "", "configuration profile")
+	return set, source
+}
+
+func report(args []string) error {
+	kind := "stats"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		kind = args[0]
+		args = args[1:]
+	}
+	if kind != "stats" && kind != "time" {
+		return fmt.Errorf("unknown report type: %s (stats, time)", kind)
+	}
+	window, usage := "30d", "report on tasks finished since (e.g. 30d, 12h), empty for all time"
+	if kind == "time" {
+		window, usage = "7d", "report on time tracked since (e.g. 7d, 12h), empty for all time"
+	}
+	set, cfgFile := newFlags("report")
+	since := set.String("since", window, usage)
+	format := set.String("format", "text", "report format: text, json or markdown (stats only)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && (kind == "time" || (*format != "json" && *format != "markdown")) {
+		return fmt.Errorf("unknown %s report format: %s", kind, *format)
+	}
+	cfg, err := loadConfig(*cfgFile)
+	if err != nil {
+		return err
+	}
+	storage, err := cfg.OpenStore(cfg.Database(), os.Stderr)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var from time.Time
+	if *since != "" {
+		window, err := durations.Parse(*since)
+		if err != nil {
+			return err
 		}
-		set := flag.NewFlagSet("cli", flag.ExitOnError)
-		cfgFile := set.String("config", "", "configuration file")
-		if err := set.Parse(args); err != nil {
+		from = now.Add(-window)
+	}
+	stacks := entities.ListStacks(storage)
+	if kind == "time" {
+		return reports.Time(stacks, from, now).Write(os.Stdout)
+	}
+	stats := reports.Stats(stacks, from, now)
+	switch *format {
+	case "json":
+		return stats.WriteJSON(os.Stdout)
+	case "markdown":
+		return stats.WriteMarkdown(os.Stdout)
+	}
+	return stats.Write(os.Stdout)
+}
+
+// session is the interactive context, the profile (and so the context) is switched while running
+type session struct {
+	lock    sync.Mutex
+	source  configSource
+	ctx     *state.Context
+	release func()
+	// stopBackups will stop the periodic backups of the current profile (if any)
+	stopBackups func()
+	// failed is set when neither profile could be opened during a switch
+	failed error
+}
+
+func (s *session) start(cfg state.Config) error {
+	ctx, release, err := startup(cfg)
+	if err != nil {
+		return err
+	}
+	if err := s.scheduleBackups(cfg); err != nil {
+		release()
+		return err
+	}
+	ctx.Switch = s.switchTo
+	s.ctx = ctx
+	s.release = release
+	return nil
+}
+
+// scheduleBackups will (re)start periodic backups at the interval of a profile
+func (s *session) scheduleBackups(cfg state.Config) error {
+	if s.stopBackups != nil {
+		s.stopBackups()
+		s.stopBackups = nil
+	}
+	if cfg.Backups.Directory == "" || cfg.Backups.Interval == "" {
+		return nil
+	}
+	interval, err := durations.Parse(cfg.Backups.Interval)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	s.stopBackups = func() {
+		ticker.Stop()
+		close(done)
+	}
+	go periodicBackups(s.current, ticker.C, done)
+	return nil
+}
+
+// current will get the current context
+func (s *session) current() *state.Context {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.ctx
+}
+
+// close will release the current context
+func (s *session) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stopBackups != nil {
+		s.stopBackups()
+		s.stopBackups = nil
+	}
+	if s.release != nil {
+		s.release()
+		s.release = nil
+	}
+}
+
+// switchTo will release the current profile and startup another, the current profile is kept (or reopened) when
+// that fails and returned with the error (no context is returned if it can't be reopened either)
+func (s *session) switchTo(profile string) (*state.Context, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	source := s.source
+	source.profile = profile
+	cfg, err := source.load()
+	if err != nil {
+		return s.ctx, err
+	}
+	// there is no prompting for a key while running
+	if err := cfg.Unlock(nil); err != nil {
+		return s.ctx, err
+	}
+	prev := s.ctx.Config
+	s.release()
+	s.release = nil
+	if err := s.start(cfg); err != nil {
+		if reopen := s.start(prev); reopen != nil {
+			s.failed = errors.Join(err, reopen)
+			return nil, s.failed
+		}
+		return s.ctx, err
+	}
+	s.source = source
+	return s.ctx, nil
+}
+
+func interactive(args []string) error {
+	set, source := newFlags("cli")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*source)
+	if err != nil {
+		return err
+	}
+	s := &session{source: *source}
+	if err := s.start(cfg); err != nil {
+		return err
+	}
+	defer s.close()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		s.close()
+		os.Exit(0)
+	}()
+	model := ui.Initialize(s.current())
+	p := tea.NewProgram(model.Backing, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	return s.failed
+}
+
+// periodicBackups will backup the current context (when the data changed since the last backup) on each tick until done
+func periodicBackups(current func() *state.Context, ticks <-chan time.Time, done <-chan struct{}) {
+	last := time.Now()
+	for {
+		var now time.Time
+		select {
+		case <-done:
+			return
+		case now = <-ticks:
+		}
+		ctx := current()
+		if ctx.Config.Backups.Directory == "" {
+			continue
+		}
+		info, err := os.Stat(ctx.Config.Database())
+		if err != nil || !info.ModTime().After(last) {
+			continue
+		}
+		last = now
+		ctx.DB.Log("backup", ctx.Config.Backup(now))
+	}
+}
+
+func backup(args []string) error {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "restore") {
+		return errors.New("backup command required: list, restore")
+	}
+	command := args[0]
+	args = args[1:]
+	name := ""
+	if command == "restore" {
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			return errors.New("backup name required (see: mayhem backup list)")
+		}
+		name = args[0]
+		args = args[1:]
+	}
+	set, cfgFile := newFlags("backup")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := cfgFile.load()
+	if err != nil {
+		return err
+	}
+	if command == "list" {
+		backups, err := cfg.ListBackups()
+		if err != nil {
 			return err
 		}
-		configFile = *cfgFile
+		for _, b := range backups {
+			fmt.Printf("%s  %s  %d\n", b.Name, b.Time.Format("2006-01-02 15:04"), b.Size)
+		}
+		return nil
 	}
-	ctx := &state.Context{}
-	ctx.Screen = display.NewScreen()
-	cfg, err := state.LoadConfig(configFile)
+	if err := cfg.Unlock(promptKey); err != nil {
+		return err
+	}
+	unlock, err := lock(cfg)
 	if err != nil {
 		return err
 	}
-	ctx.Config = cfg
-	if !cfg.Data.NoLock {
-		lockFile := filepath.Join(ctx.Config.Data.Directory, "lockfile")
-		if state.PathExists(lockFile) {
-			return fmt.Errorf("locked: %s exists", lockFile)
+	defer unlock()
+	restored, err := cfg.RestoreBackup(name, time.Now())
+	if err != nil {
+		return err
+	}
+	if cfg.Data.Git {
+		repo, err := revisions.Init(cfg.Data.Directory, cfg.HistoryFiles())
+		if err == nil {
+			err = repo.Commit("restore backup: " + restored.Name)
 		}
-		lock := fmt.Sprintf("%d %s", os.Getpid(), time.Now().String())
-		if err := os.WriteFile(lockFile, []byte(lock), 0o644); err != nil {
+		if err != nil && !errors.Is(err, revisions.ErrNoGit) {
 			return err
 		}
-		sigs := make(chan os.Signal, 1)
+	}
+	return nil
+}
 
-		signal.Notify(sigs, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
-		go func() {
-			<-sigs
-			os.Remove(lockFile)
-			os.Exit(0)
-		}()
-		defer os.Remove(lockFile)
+func remind(args []string) error {
+	set, cfgFile := newFlags("remind")
+	watch := set.Bool("watch", false, "keep running and check for reminders periodically")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgFile)
+	if err != nil {
+		return err
+	}
+	lead := cfg.Reminders.Lead
+	if lead == "" {
+		lead = remindLead
+	}
+	leads, err := durations.ParseList(lead)
+	if err != nil {
+		return err
+	}
+	notifier, err := reminders.NewNotifier(cfg.Reminders.Notifier, cfg.Reminders.Command, cfg.Reminders.File)
+	if err != nil {
+		return err
 	}
-	if ctx.Config.Backups.Directory != "" {
-		if err := ctx.Config.Backup(time.Now()); err != nil {
+	interval := remindInterval
+	if cfg.Reminders.Interval != "" {
+		interval, err = durations.Parse(cfg.Reminders.Interval)
+		if err != nil {
 			return err
 		}
 	}
-	file := ctx.Config.Database()
-	f, err := os.OpenFile(filepath.Join(ctx.Config.Data.Directory, "log.txt"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	check := func() error {
+		storage, err := cfg.OpenStore(cfg.Database(), os.Stderr)
+		if err != nil {
+			return err
+		}
+		fired, err := reminders.LoadFired(cfg.RemindersDatabase())
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		fired.Prune(now)
+		err = reminders.Send(reminders.Due(entities.ListStacks(storage), leads, fired, now), notifier, fired, now)
+		return errors.Join(err, fired.Save(cfg.RemindersDatabase()))
+	}
+	if !*watch {
+		return check()
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := check(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+		select {
+		case <-sigs:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func history(args []string) error {
+	set, cfgFile := newFlags("history")
+	limit := set.Int("limit", historyLimit, "number of revisions to list (0 for all)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := cfgFile.load()
+	if err != nil {
+		return err
+	}
+	repo, err := revisions.Open(cfg.Data.Directory, cfg.HistoryFiles())
+	if err != nil {
+		return err
+	}
+	revs, err := repo.Log(*limit)
+	if err != nil {
+		return err
+	}
+	for _, rev := range revs {
+		fmt.Printf("%s  %s  %s\n", rev.Hash, rev.Time.Format("2006-01-02 15:04"), rev.Message)
+	}
+	return nil
+}
+
+func restore(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return errors.New("revision required (see: mayhem history)")
+	}
+	set, cfgFile := newFlags("restore")
+	if err := set.Parse(args[1:]); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgFile)
+	if err != nil {
+		return err
+	}
+	unlock, err := lock(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	repo, err := revisions.Open(cfg.Data.Directory, cfg.HistoryFiles())
+	if err != nil {
+		return err
+	}
+	return repo.Restore(args[0], func() error {
+		for _, file := range []string{cfg.Database(), cfg.ArchiveDatabase()} {
+			if _, err := cfg.OpenStore(file, io.Discard); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func convert(args []string, encrypt bool) error {
+	name := "decrypt"
+	if encrypt {
+		name = "encrypt"
+	}
+	set, cfgFile := newFlags(name)
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := cfgFile.load()
+	if err != nil {
+		return err
+	}
+	unlock, err := lock(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	prompt := promptKey
+	if encrypt {
+		// a typo would make the data unreadable, so the key is confirmed
+		prompt = func() (string, error) {
+			key, err := promptKey()
+			if err != nil {
+				return "", err
+			}
+			confirm, err := promptKey()
+			if err != nil {
+				return "", err
+			}
+			if key != confirm {
+				return "", errors.New("keys do not match")
+			}
+			return key, nil
+		}
+	}
+	cipher, err := cfg.Cipher(prompt)
+	if err != nil {
+		return err
+	}
+	files, err := cfg.Convert(cipher, encrypt)
+	for _, file := range files {
+		fmt.Fprintf(os.Stderr, "%sed: %s\n", name, file)
+	}
+	if err != nil {
+		return err
+	}
+	if encrypt && len(files) > 0 {
+		if state.PathExists(filepath.Join(cfg.Data.Directory, ".git")) {
+			fmt.Fprintln(os.Stderr, "warning: the git history still has unencrypted revisions")
+		}
+		if !cfg.Data.Encryption.Enabled {
+			fmt.Fprintln(os.Stderr, "warning: data.encryption.enabled must be set to use the encrypted data")
+		}
+	}
+	return nil
+}
+
+// lock will acquire the lock on the data directory (unless disabled), the returned func releases it
+func lock(cfg state.Config) (func(), error) {
+	if cfg.Data.NoLock {
+		return func() {}, nil
+	}
+	lockFile := filepath.Join(cfg.Data.Directory, "lockfile")
+	if state.PathExists(lockFile) {
+		return nil, fmt.Errorf("locked: %s exists", lockFile)
+	}
+	lock := fmt.Sprintf("%d %s", os.Getpid(), time.Now().String())
+	if err := os.WriteFile(lockFile, []byte(lock), 0o644); err != nil {
+		return nil, err
+	}
+	return func() { os.Remove(lockFile) }, nil
+}
+
+func profiles(args []string) error {
+	set, source := newFlags("profiles")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := source.load()
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	storage := backend.NewMemoryBased(file, ctx.Config.Data.Pretty, f)
-	if state.PathExists(file) {
-		if err := backend.Load[entities.Stack, entities.Task](storage); err != nil {
+	fmt.Printf("(default)  %s\n", cfg.Data.Directory)
+	for _, name := range cfg.ProfileNames() {
+		profile := *source
+		profile.profile = name
+		profiled, err := profile.load()
+		if err != nil {
 			return err
 		}
+		fmt.Printf("%s  %s\n", name, profiled.Data.Directory)
 	}
-	ctx.DB = storage
-	err = func() error {
-		model := ui.Initialize(ctx)
-		p := tea.NewProgram(model.Backing, tea.WithAltScreen())
+	return nil
+}
 
-		if _, err := p.Run(); err != nil {
+func template(args []string) error {
+	if len(args) == 0 || !slices.Contains([]string{"list", "apply", "save"}, args[0]) {
+		return errors.New("template command required: list, apply, save")
+	}
+	command := args[0]
+	args = args[1:]
+	name := ""
+	if command != "list" {
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			return errors.New("template name required")
+		}
+		name = args[0]
+		args = args[1:]
+	}
+	set, source := newFlags("template")
+	title := set.String("stack", "", "stack title (apply: added to this stack, created when missing, defaults to the template stack)")
+	task := set.String("task", "", "save: only this task (by title) of the stack, as a task template")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := source.load()
+	if err != nil {
+		return err
+	}
+	dir := cfg.TemplatesDirectory()
+	switch command {
+	case "list":
+		names, err := templates.List(dir)
+		if err != nil {
 			return err
 		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
 		return nil
+	case "save":
+		if *title == "" {
+			return errors.New("stack required to save as a template")
+		}
+		if err := cfg.Unlock(promptKey); err != nil {
+			return err
+		}
+		storage, err := cfg.OpenStore(cfg.Database(), io.Discard)
+		if err != nil {
+			return err
+		}
+		stack, ok := findStack(entities.ListStacks(storage), *title)
+		if !ok {
+			return fmt.Errorf("unknown stack: %s", *title)
+		}
+		if *task == "" {
+			return templates.FromStack(stack).Save(dir, name)
+		}
+		idx := slices.IndexFunc(stack.Tasks, func(t entities.Task) bool {
+			return t.Title == *task
+		})
+		if idx < 0 {
+			return fmt.Errorf("unknown task: %s", *task)
+		}
+		return templates.FromTasks(stack.Tasks[idx:idx+1], stack.Sort).Save(dir, name)
+	}
+	t, err := templates.Load(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Unlock(promptKey); err != nil {
+		return err
+	}
+	ctx, release, err := startup(cfg)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if *title == "" {
+		*title = t.Stack
+	}
+	stack, ok := findStack(entities.ListStacks(ctx.DB), *title)
+	if !ok {
+		stack = entities.NewStack(ctx.DB)
+		stack.Title = *title
+		stack = stack.Save(ctx.DB).(entities.Stack)
+	}
+	tasks, err := t.Apply(ctx.DB, stack, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("added %d task(s) to %s\n", len(tasks), stack.Title)
+	return nil
+}
+
+// findStack will find a stack by title
+func findStack(stacks []entities.Stack, title string) (entities.Stack, bool) {
+	for _, stack := range stacks {
+		if stack.Title == title {
+			return stack, true
+		}
+	}
+	return entities.Stack{}, false
+}
+
+func serve(args []string) error {
+	set, source := newFlags("serve")
+	listen := set.String("listen", "", "address to listen on (host:port or unix:/path/to/socket)")
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*source)
+	if err != nil {
+		return err
+	}
+	ctx, release, err := startup(cfg)
+	if err != nil {
+		return err
+	}
+	defer release()
+	address := *listen
+	if address == "" {
+		address = ctx.Config.Server.Listen
+	}
+	network := "tcp"
+	if after, ok := strings.CutPrefix(address, "unix:"); ok {
+		network = "unix"
+		address = after
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: server.New(ctx.DB, ctx.Config.Server.Token), ReadHeaderTimeout: 10 * time.Second}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		srv.Shutdown(context.Background())
 	}()
+	fmt.Fprintf(os.Stderr, "listening on %s\n", listener.Addr())
+	if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
 
-	return err
+// startup will acquire the lock and open the store for a (loaded) configuration (release must be called when done)
+func startup(cfg state.Config) (*state.Context, func(), error) {
+	var closers []func()
+	release := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+		closers = nil
+	}
+	ctx, err := func() (*state.Context, error) {
+		ctx := &state.Context{}
+		ctx.Screen = display.NewScreen()
+		ctx.Config = cfg
+		var err error
+		if ctx.Screen.Theme, err = cfg.Theme(); err != nil {
+			return nil, err
+		}
+		unlock, err := lock(cfg)
+		if err != nil {
+			return nil, err
+		}
+		closers = append(closers, unlock)
+		if ctx.Config.Backups.Directory != "" {
+			if err := ctx.Config.Backup(time.Now()); err != nil {
+				return nil, err
+			}
+		}
+		file := ctx.Config.Database()
+		f, err := os.OpenFile(ctx.Config.LogFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		closers = append(closers, func() { f.Close() })
+		ctx.Logger = f
+		storage, err := ctx.Config.OpenStore(file, f)
+		if err != nil {
+			return nil, err
+		}
+		var db backend.Store = storage
+		if ctx.Config.Data.Git {
+			// without git (or a usable repository) mayhem still runs, the failure is logged
+			repo, err := revisions.Init(ctx.Config.Data.Directory, ctx.Config.HistoryFiles())
+			if err == nil {
+				// pick up anything changed outside of mayhem
+				err = repo.Commit("update data (external changes)")
+			}
+			if err != nil {
+				storage.Log("git", err)
+			} else {
+				debounce := commitDebounce
+				if ctx.Config.Data.Debounce != "" {
+					debounce, err = time.ParseDuration(ctx.Config.Data.Debounce)
+					if err != nil {
+						return nil, err
+					}
+				}
+				wrapped := revisions.New(storage, repo, debounce)
+				closers = append(closers, wrapped.Close)
+				db = wrapped
+				ctx.OnArchive = func(archive backend.Store) backend.Store {
+					wrapped := revisions.New(archive, repo, debounce)
+					closers = append(closers, wrapped.Close)
+					return wrapped
+				}
+			}
+		}
+		if ctx.Config.Trash.Retention != "" {
+			retention, err := durations.Parse(ctx.Config.Trash.Retention)
+			if err != nil {
+				return nil, err
+			}
+			entities.PurgeTrash(db, time.Now().Add(-retention))
+		}
+		if ctx.Config.Archive.Finished != "" {
+			age, err := durations.Parse(ctx.Config.Archive.Finished)
+			if err != nil {
+				return nil, err
+			}
+			if tasks := entities.FinishedBefore(db, time.Now().Add(-age)); len(tasks) > 0 {
+				archive, err := ctx.Archive()
+				if err != nil {
+					return nil, err
+				}
+				entities.ArchiveTasks(db, archive, tasks)
+			}
+		}
+		ctx.DB = db
+		if commands := ctx.Config.HookCommands(); len(commands) > 0 {
+			timeout := hookTimeout
+			if ctx.Config.Hooks.Timeout != "" {
+				timeout, err = time.ParseDuration(ctx.Config.Hooks.Timeout)
+				if err != nil {
+					return nil, err
+				}
+			}
+			wrapped := hooks.New(db, commands, timeout)
+			closers = append(closers, wrapped.Wait)
+			ctx.DB = wrapped
+		}
+		return ctx, nil
+	}()
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return ctx, release, nil
 }
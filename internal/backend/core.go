@@ -4,7 +4,7 @@ package backend
 type (
 	// Map is a key/value (id->data) map
 	Map map[string]Data
-	// Data is the actual payload for data
+	// Data is the actual payload for data, parents have (possibly empty) children which may be nested parents
 	Data struct {
 		Node     any
 		Children Map
@@ -12,11 +12,29 @@ type (
 	// Store defines the interface for interacting with a backend
 	Store interface {
 		Add(string, any)
+		Nest(string, string, any)
 		AddChild(string, string, any)
 		Remove(string)
 		RemoveChild(string, string)
 		Get() []Data
 		Errored() bool
 		Log(string, error)
+		Batch(func())
 	}
 )
+
+// Flatten will get all parents (the given data and all nested parents, depth first)
+func Flatten(data []Data) []Data {
+	var result []Data
+	for _, item := range data {
+		result = append(result, item)
+		var nested []Data
+		for _, child := range item.Children {
+			if child.Children != nil {
+				nested = append(nested, child)
+			}
+		}
+		result = append(result, Flatten(nested)...)
+	}
+	return result
+}
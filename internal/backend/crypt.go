@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+const (
+	// iterations for deriving the key from a passphrase
+	iterations = 600_000
+	saltSize   = 16
+	keySize    = 32
+)
+
+var (
+	magic = []byte("MAYHEM-AES-GCM-1\n")
+	// ErrEncrypted indicates encrypted data was found without a key
+	ErrEncrypted = errors.New("data is encrypted, a key is required")
+)
+
+type (
+	// Cipher encrypts/decrypts the data file
+	Cipher interface {
+		Encrypt([]byte) ([]byte, error)
+		Decrypt([]byte) ([]byte, error)
+	}
+
+	// Passphrase encrypts with AES-GCM, the key is derived (PBKDF2) from a passphrase
+	Passphrase struct {
+		passphrase string
+		salt       []byte
+		key        []byte
+	}
+)
+
+// NewPassphrase will create a passphrase based cipher
+func NewPassphrase(passphrase string) (*Passphrase, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+	return &Passphrase{passphrase: passphrase}, nil
+}
+
+// IsEncrypted indicates if data was encrypted by a passphrase cipher
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// derive will get the key for a salt, the key is only derived again when the salt changes
+func (p *Passphrase) derive(salt []byte) ([]byte, error) {
+	if p.key != nil && bytes.Equal(p.salt, salt) {
+		return p.key, nil
+	}
+	key, err := pbkdf2.Key(sha256.New, p.passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+	p.salt = salt
+	p.key = key
+	return key, nil
+}
+
+func (p *Passphrase) gcm(salt []byte) (cipher.AEAD, error) {
+	key, err := p.derive(salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt will encrypt data (the salt is kept across writes, the nonce is not)
+func (p *Passphrase) Encrypt(data []byte) ([]byte, error) {
+	salt := p.salt
+	if salt == nil {
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	}
+	gcm, err := p.gcm(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header := append(append(bytes.Clone(magic), salt...), nonce...)
+	return gcm.Seal(header, nonce, data, magic), nil
+}
+
+// Decrypt will decrypt data
+func (p *Passphrase) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, errors.New("data is not encrypted")
+	}
+	data = data[len(magic):]
+	if len(data) < saltSize {
+		return nil, errors.New("invalid encrypted data")
+	}
+	salt := bytes.Clone(data[:saltSize])
+	gcm, err := p.gcm(salt)
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted data")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], magic)
+	if err != nil {
+		return nil, errors.New("unable to decrypt (wrong key?)")
+	}
+	return plain, nil
+}
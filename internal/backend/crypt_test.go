@@ -0,0 +1,72 @@
+package backend_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+func TestPassphrase(t *testing.T) {
+	if _, err := backend.NewPassphrase(""); err == nil {
+		t.Error("empty passphrase")
+	}
+	p, _ := backend.NewPassphrase("secret")
+	encrypted, err := p.Encrypt([]byte("data"))
+	if err != nil || !backend.IsEncrypted(encrypted) || bytes.Contains(encrypted, []byte("data")) {
+		t.Errorf("invalid encrypt: %v", err)
+	}
+	again, _ := p.Encrypt([]byte("data"))
+	if bytes.Equal(encrypted, again) {
+		t.Error("nonce should not be reused")
+	}
+	if b, err := p.Decrypt(encrypted); err != nil || string(b) != "data" {
+		t.Errorf("invalid decrypt: %s (%v)", b, err)
+	}
+	wrong, _ := backend.NewPassphrase("wrong")
+	if _, err := wrong.Decrypt(encrypted); err == nil {
+		t.Error("wrong key should fail")
+	}
+	if _, err := p.Decrypt([]byte("data")); err == nil {
+		t.Error("unencrypted data should fail")
+	}
+	if _, err := p.Decrypt(encrypted[:len(encrypted)-1]); err == nil {
+		t.Error("truncated data should fail")
+	}
+}
+
+func TestEncryptedStore(t *testing.T) {
+	var buf bytes.Buffer
+	type parent *int
+	type child *int
+	path := filepath.Join(t.TempDir(), "data.json")
+	p, _ := backend.NewPassphrase("secret")
+	m := backend.NewMemoryBased(path, false, &buf)
+	m.SetCipher(p)
+	m.Add("1", 1)
+	m.AddChild("1", "2", 2)
+	b, _ := os.ReadFile(path)
+	if !backend.IsEncrypted(b) || strings.Contains(string(b), "Node") {
+		t.Errorf("not encrypted: %s", b)
+	}
+	if err := backend.Load[parent, child](backend.NewMemoryBased(path, false, &buf)); !errors.Is(err, backend.ErrEncrypted) {
+		t.Errorf("encrypted data loaded without a key: %v", err)
+	}
+	loaded := backend.NewMemoryBased(path, false, &buf)
+	loaded.SetCipher(p)
+	if err := backend.Load[parent, child](loaded); err != nil || len(loaded.Get()) != 1 || len(loaded.Get()[0].Children) != 1 {
+		t.Errorf("invalid load: %v", err)
+	}
+	// unencrypted data is loaded (and encrypted on the next change)
+	os.WriteFile(path, []byte(`{"1":{"Node":1,"Children":{}}}`), 0o644)
+	if err := backend.Load[parent, child](loaded); err != nil {
+		t.Errorf("invalid load: %v", err)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
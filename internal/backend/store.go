@@ -1,23 +1,33 @@
 package backend
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
 // MemoryBased is a memory-based backend (that can sync to JSON file)
 type MemoryBased struct {
-	data     Map
+	data Map
+	// children maps (leaf) children to their parent
 	children map[string]string
+	// parents maps nested parents to their parent (top-level parents are not mapped)
+	parents  map[string]string
 	logger   io.Writer
 	pretty   bool
 	file     string
 	errored  bool
+	batching int
+	dirty    bool
+	cipher   Cipher
+	// logging may happen from other goroutines (e.g. hooks)
+	logLock sync.Mutex
 }
 
 // NewMemoryBased will create a new memory-based backend
@@ -27,25 +37,36 @@ func NewMemoryBased(file string, pretty bool, logger io.Writer) *MemoryBased {
 		pretty:   pretty,
 		file:     file,
 		children: make(map[string]string),
+		parents:  make(map[string]string),
 		logger:   logger,
 		errored:  false,
 	}
 }
 
-// Load will load data into a memory backed store from file
+// SetCipher will encrypt the file (when syncing) and decrypt it (when loading)
+func (m *MemoryBased) SetCipher(c Cipher) {
+	m.cipher = c
+}
+
+// Load will load data into a memory backed store from file, nodes with children (even if empty) are parents (P) at any depth
 func Load[P, C any](m *MemoryBased) error {
 	if m.file == "" {
 		return nil
 	}
-	file, err := os.Open(m.file)
+	b, err := os.ReadFile(m.file)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
+	if IsEncrypted(b) {
+		if m.cipher == nil {
+			return ErrEncrypted
+		}
+		if b, err = m.cipher.Decrypt(b); err != nil {
+			return err
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
 	decoder.DisallowUnknownFields()
-
 	type raw struct {
 		Node     json.RawMessage
 		Children map[string]raw
@@ -54,54 +75,133 @@ func Load[P, C any](m *MemoryBased) error {
 	if err := decoder.Decode(&data); err != nil {
 		return err
 	}
-	for k, v := range data {
+	var load func(string, raw) (Data, error)
+	load = func(id string, v raw) (Data, error) {
 		var parent P
 		if err := json.Unmarshal(v.Node, &parent); err != nil {
-			return err
+			return Data{}, err
 		}
 		children := make(Map)
-		if v.Children != nil {
-			for ck, cv := range v.Children {
-				var child C
-				if err := json.Unmarshal(cv.Node, &child); err != nil {
-					return err
+		for ck, cv := range v.Children {
+			if cv.Children != nil {
+				nested, err := load(ck, cv)
+				if err != nil {
+					return Data{}, err
 				}
-				m.children[ck] = k
-				children[ck] = Data{Node: child}
+				m.parents[ck] = id
+				children[ck] = nested
+				continue
+			}
+			var child C
+			if err := json.Unmarshal(cv.Node, &child); err != nil {
+				return Data{}, err
 			}
+			m.children[ck] = id
+			children[ck] = Data{Node: child}
+		}
+		return Data{Node: parent, Children: children}, nil
+	}
+	for k, v := range data {
+		d, err := load(k, v)
+		if err != nil {
+			return err
 		}
-		m.data[k] = Data{Node: parent, Children: children}
+		m.data[k] = d
 	}
 	return nil
 }
 
+// holder will get the map holding a parent (the top-level data or the children of its parent)
+func (m *MemoryBased) holder(id string) (Map, bool) {
+	parent, nested := m.parents[id]
+	if !nested {
+		_, ok := m.data[id]
+		return m.data, ok
+	}
+	h, ok := m.holder(parent)
+	if !ok {
+		return nil, false
+	}
+	_, ok = h[parent].Children[id]
+	return h[parent].Children, ok
+}
+
+// childrenOf will get the children of a parent (at any depth)
+func (m *MemoryBased) childrenOf(parent string) (Map, bool) {
+	h, ok := m.holder(parent)
+	if !ok {
+		return nil, false
+	}
+	return h[parent].Children, true
+}
+
+// descends indicates if a parent is nested (at any depth) under an ancestor
+func (m *MemoryBased) descends(id, ancestor string) bool {
+	for parent, ok := m.parents[id]; ok; parent, ok = m.parents[parent] {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
 // Errored indicates if errors were logged
 func (m *MemoryBased) Errored() bool {
+	m.logLock.Lock()
+	defer m.logLock.Unlock()
 	return m.errored
 }
 
-// Add will add a new entity
+// Add will add a new entity (or update it, moving it to the top-level when nested)
 func (m *MemoryBased) Add(id string, data any) {
+	m.nest("add", "", id, data)
+}
+
+// Nest will add (or update/move) an entity under a parent (an empty parent is the top-level), it can then have children
+func (m *MemoryBased) Nest(parent, id string, data any) {
+	m.nest("nest", parent, id, data)
+}
+
+func (m *MemoryBased) nest(cat, parent, id string, data any) {
 	err := func() error {
 		if strings.TrimSpace(id) == "" {
 			return errors.New("id is empty")
 		}
-		v, ok := m.data[id]
-		if !ok {
-			v = Data{}
+		target := m.data
+		if parent != "" {
+			if parent == id || m.descends(parent, id) {
+				return fmt.Errorf("can not nest under itself: %v", id)
+			}
+			children, ok := m.childrenOf(parent)
+			if !ok {
+				return fmt.Errorf("parent not found: %v", parent)
+			}
+			target = children
+		}
+		v := Data{}
+		if h, ok := m.holder(id); ok {
+			v = h[id]
+			delete(h, id)
+		}
+		if v.Children == nil {
 			v.Children = make(Map)
 		}
 		v.Node = data
-		m.data[id] = v
+		target[id] = v
+		if parent == "" {
+			delete(m.parents, id)
+		} else {
+			m.parents[id] = parent
+		}
 		return nil
 	}()
-	m.Log("add", err)
+	m.Log(cat, err)
 	if err == nil {
 		m.sync()
 	}
 }
 
-// AddChild will add a child entity
+// AddChild will add a child entity (under a parent at any depth)
 func (m *MemoryBased) AddChild(parent, id string, data any) {
 	err := func() error {
 		if strings.TrimSpace(parent) == "" {
@@ -110,18 +210,21 @@ func (m *MemoryBased) AddChild(parent, id string, data any) {
 		if strings.TrimSpace(id) == "" {
 			return errors.New("id is empty")
 		}
-		if _, ok := m.data[parent]; !ok {
+		children, ok := m.childrenOf(parent)
+		if !ok {
 			return fmt.Errorf("parent not found: %v", parent)
 		}
-		if v, ok := m.children[id]; ok {
-			delete(m.data[v].Children, id)
+		if v, ok := m.children[id]; ok && v != parent {
+			if prev, ok := m.childrenOf(v); ok {
+				delete(prev, id)
+			}
 		}
-		c, ok := m.data[parent].Children[id]
+		c, ok := children[id]
 		if !ok {
 			c = Data{}
 		}
 		c.Node = data
-		m.data[parent].Children[id] = c
+		children[id] = c
 		m.children[id] = parent
 		return nil
 	}()
@@ -131,10 +234,26 @@ func (m *MemoryBased) AddChild(parent, id string, data any) {
 	}
 }
 
-// Remove will remove an entity
+// Remove will remove an entity (and everything nested under it)
 func (m *MemoryBased) Remove(id string) {
 	err := func() error {
-		delete(m.data, id)
+		h, ok := m.holder(id)
+		if !ok {
+			return nil
+		}
+		var forget func(string, Data)
+		forget = func(id string, d Data) {
+			delete(m.parents, id)
+			for k, v := range d.Children {
+				if v.Children != nil {
+					forget(k, v)
+				} else if m.children[k] == id {
+					delete(m.children, k)
+				}
+			}
+		}
+		forget(id, h[id])
+		delete(h, id)
 		return nil
 	}()
 	m.Log("remove", err)
@@ -143,13 +262,17 @@ func (m *MemoryBased) Remove(id string) {
 	}
 }
 
-// RemoveChild will remove a child entity
+// RemoveChild will remove a child entity (under a parent at any depth)
 func (m *MemoryBased) RemoveChild(parent, id string) {
 	err := func() error {
-		if _, ok := m.data[parent]; !ok {
+		children, ok := m.childrenOf(parent)
+		if !ok {
 			return fmt.Errorf("parent not found: %v", parent)
 		}
-		delete(m.data[parent].Children, id)
+		delete(children, id)
+		if m.children[id] == parent {
+			delete(m.children, id)
+		}
 		return nil
 	}()
 	m.Log("removechild", err)
@@ -158,7 +281,7 @@ func (m *MemoryBased) RemoveChild(parent, id string) {
 	}
 }
 
-// Get will return the backing data
+// Get will return the backing data (top-level parents, see Flatten for all parents)
 func (m *MemoryBased) Get() []Data {
 	var data []Data
 	for _, v := range m.data {
@@ -172,32 +295,71 @@ func (m *MemoryBased) Log(cat string, err error) {
 	if err == nil {
 		return
 	}
+	m.logLock.Lock()
+	defer m.logLock.Unlock()
 	m.errored = true
 	fmt.Fprintf(m.logger, "[%s] %s: %v\n", time.Now().Format("2006-01-02T15:04:05"), cat, err)
 }
 
+// Batch will run a set of changes and only sync once all changes are done
+func (m *MemoryBased) Batch(changes func()) {
+	m.batching++
+	defer func() {
+		m.batching--
+		if m.batching == 0 && m.dirty {
+			m.dirty = false
+			m.sync()
+		}
+	}()
+	changes()
+}
+
 func (m *MemoryBased) sync() {
 	if m.file == "" {
 		return
 	}
+	if m.batching > 0 {
+		m.dirty = true
+		return
+	}
 	err := func() error {
-		tmpFile := m.file + ".tmp"
-		defer func() {
-			os.Remove(tmpFile)
-		}()
-		file, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0o644)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-		encoder := json.NewEncoder(file)
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
 		if m.pretty {
 			encoder.SetIndent("", "  ")
 		}
 		if err := encoder.Encode(m.data); err != nil {
 			return err
 		}
-		return os.Rename(tmpFile, m.file)
+		b := buf.Bytes()
+		if m.cipher != nil {
+			encrypted, err := m.cipher.Encrypt(b)
+			if err != nil {
+				return err
+			}
+			b = encrypted
+		}
+		return WriteFile(m.file, b)
 	}()
 	m.Log("sync", err)
 }
+
+// WriteFile will write a file, replacing it only once the write is complete
+func WriteFile(file string, data []byte) error {
+	tmpFile := file + ".tmp"
+	defer func() {
+		os.Remove(tmpFile)
+	}()
+	f, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
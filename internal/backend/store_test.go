@@ -269,3 +269,89 @@ func TestLoad(t *testing.T) {
 		t.Errorf("invalid output: %s", s)
 	}
 }
+
+func TestBatch(t *testing.T) {
+	var buf bytes.Buffer
+	path := "testdata"
+	os.MkdirAll(path, os.ModePerm)
+	path = filepath.Join(path, "batch.json")
+	os.Remove(path)
+	m := backend.NewMemoryBased(path, false, &buf)
+	m.Batch(func() {
+		m.Add("1", 1)
+		m.Batch(func() {
+			m.AddChild("1", "2", 2)
+		})
+		if _, err := os.Stat(path); err == nil {
+			t.Error("synced during batch")
+		}
+		m.AddChild("1", "3", 3)
+	})
+	if m.Errored() {
+		t.Error("invalid batch")
+	}
+	b, _ := os.ReadFile(path)
+	if s := strings.TrimSpace(string(b)); s != `{"1":{"Node":1,"Children":{"2":{"Node":2,"Children":null},"3":{"Node":3,"Children":null}}}}` {
+		t.Errorf("invalid output: %s", s)
+	}
+	os.Remove(path)
+	m.Batch(func() {})
+	if _, err := os.Stat(path); err == nil {
+		t.Error("synced without changes")
+	}
+}
+
+func TestNest(t *testing.T) {
+	var buf bytes.Buffer
+	path := "testdata"
+	os.MkdirAll(path, os.ModePerm)
+	path = filepath.Join(path, "nest.json")
+	os.Remove(path)
+	m := backend.NewMemoryBased(path, false, &buf)
+	m.Add("1", 1)
+	m.Nest("1", "2", 2)
+	m.Nest("2", "3", 3)
+	m.AddChild("3", "x", 5)
+	if m.Errored() {
+		t.Error("invalid nest")
+	}
+	if len(m.Get()) != 1 || len(backend.Flatten(m.Get())) != 3 {
+		t.Error("invalid nest")
+	}
+	m.Nest("3", "1", 1)
+	if !m.Errored() {
+		t.Error("invalid error set")
+	}
+	m.Nest("x", "2", 2)
+	if len(strings.Split(strings.TrimSpace(buf.String()), "\n")) != 2 {
+		t.Errorf("invalid errors: %s", buf.String())
+	}
+	b, _ := os.ReadFile(path)
+	const nested = `{"1":{"Node":1,"Children":{"2":{"Node":2,"Children":{"3":{"Node":3,"Children":{"x":{"Node":5,"Children":null}}}}}}}}`
+	if s := strings.TrimSpace(string(b)); s != nested {
+		t.Errorf("invalid output: %s", s)
+	}
+	type parent *int
+	type child *int
+	loaded := backend.NewMemoryBased(path, false, &buf)
+	if err := backend.Load[parent, child](loaded); err != nil {
+		t.Errorf("invalid load: %v", err)
+	}
+	// move to the top-level (keeping the nested children)
+	loaded.Add("3", 4)
+	loaded.AddChild("3", "y", 6)
+	b, _ = os.ReadFile(path)
+	if s := strings.TrimSpace(string(b)); s != `{"1":{"Node":1,"Children":{"2":{"Node":2,"Children":{}}}},"3":{"Node":4,"Children":{"x":{"Node":5,"Children":null},"y":{"Node":6,"Children":null}}}}` {
+		t.Errorf("invalid output: %s", s)
+	}
+	loaded.Remove("1")
+	loaded.RemoveChild("3", "x")
+	b, _ = os.ReadFile(path)
+	if s := strings.TrimSpace(string(b)); s != `{"3":{"Node":4,"Children":{"y":{"Node":6,"Children":null}}}}` {
+		t.Errorf("invalid output: %s", s)
+	}
+	loaded.Nest("2", "z", 1)
+	if len(loaded.Get()) != 1 {
+		t.Error("invalid nest under removed parent")
+	}
+}
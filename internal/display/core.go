@@ -2,6 +2,8 @@
 package display
 
 import (
+	"math"
+
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -16,6 +18,17 @@ type (
 		Table  struct {
 			ViewHeight int
 		}
+		Theme Theme
+		// Panes are where the boxes were last rendered (left to right)
+		Panes []Pane
+	}
+
+	// Pane is the position/size of a rendered box
+	Pane struct {
+		X      int
+		Y      int
+		Width  int
+		Height int
 	}
 )
 
@@ -30,6 +43,8 @@ const (
 	StackTableType TableType = iota
 	// TaskTableType defines the task table definition
 	TaskTableType
+	// tableBodyOffset is where table rows start in a box (after the border and header)
+	tableBodyOffset = 3
 )
 
 var (
@@ -88,6 +103,7 @@ var (
 func NewScreen() *Screen {
 	s := &Screen{}
 	s.Table.ViewHeight = 25
+	s.Theme = DefaultTheme()
 	return s
 }
 
@@ -191,3 +207,58 @@ func (s *Screen) EmptyTaskView() string {
 func (s *Screen) EmptyDetailsView() string {
 	return s.EmptyDetailsStyle().Render("Press either '→' or 'l' key to see task details")
 }
+
+// ListWindow will get the (start, end) range of a list of items to render so that the focused item is visible
+func ListWindow(total, focus, size int) (int, int) {
+	if total <= size {
+		return 0, total
+	}
+	start := focus - size/2
+	if start < 0 {
+		start = 0
+	}
+	if start+size > total {
+		start = total - size
+	}
+	return start, start + size
+}
+
+// Layout will record the panes of boxes joined horizontally (and centered, as lipgloss.JoinHorizontal does)
+func (s *Screen) Layout(boxes ...string) {
+	s.Panes = nil
+	height := 0
+	for _, box := range boxes {
+		height = max(height, lipgloss.Height(box))
+	}
+	x := 0
+	for _, box := range boxes {
+		w, h := lipgloss.Size(box)
+		y := int(math.Round(float64(height-h) * float64(lipgloss.Center)))
+		s.Panes = append(s.Panes, Pane{X: x, Y: y, Width: w, Height: h})
+		x += w
+	}
+}
+
+// PaneAt will get the index of the pane at a position (-1 when there is none)
+func (s *Screen) PaneAt(x, y int) int {
+	for idx, pane := range s.Panes {
+		if x >= pane.X && x < pane.X+pane.Width && y >= pane.Y && y < pane.Y+pane.Height {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Line will get the line within the pane (inside the border) of a position
+func (p Pane) Line(y int) int {
+	return y - p.Y - 1
+}
+
+// TableRow will get the (visible) table row of a position, the row may be beyond the rows shown
+func (p Pane) TableRow(y int) int {
+	row := y - p.Y - tableBodyOffset
+	if row < 0 {
+		return -1
+	}
+	return row
+}
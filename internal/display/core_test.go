@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/display"
 )
 
@@ -123,3 +124,50 @@ func TestEmptyDetailsView(t *testing.T) {
 		t.Errorf("invalid render: %s", val)
 	}
 }
+
+func TestListWindow(t *testing.T) {
+	for _, check := range []struct {
+		total, focus, size, start, end int
+	}{
+		{5, 0, 10, 0, 5},
+		{20, 0, 10, 0, 10},
+		{20, 12, 10, 7, 17},
+		{20, 19, 10, 10, 20},
+	} {
+		start, end := display.ListWindow(check.total, check.focus, check.size)
+		if start != check.start || end != check.end {
+			t.Errorf("invalid window: %v (%d, %d)", check, start, end)
+		}
+	}
+}
+
+func TestThemePriority(t *testing.T) {
+	theme := display.DefaultTheme()
+	if level := theme.Priority(2); level.Name != "2" {
+		t.Errorf("invalid default level: %v", level)
+	}
+	theme.Priorities = []display.PriorityLevel{display.NewPriorityLevel("Low", "8"), display.NewPriorityLevel("", "")}
+	if level := theme.Priority(0); level.Name != "Low" || level.Style.GetForeground() != lipgloss.Color("8") {
+		t.Errorf("invalid level: %v", level)
+	}
+	if level := theme.Priority(1); level.Name != "1" {
+		t.Errorf("invalid unnamed level: %v", level)
+	}
+	if level := theme.Priority(4); level.Name != "4" {
+		t.Errorf("invalid unset level: %v", level)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	s := display.NewScreen()
+	s.Layout("ab\ncd\nef\ngh", "x\ny")
+	if len(s.Panes) != 2 || s.Panes[1] != (display.Pane{X: 2, Y: 1, Width: 1, Height: 2}) {
+		t.Errorf("invalid layout: %v", s.Panes)
+	}
+	if s.PaneAt(1, 3) != 0 || s.PaneAt(2, 1) != 1 || s.PaneAt(2, 0) != -1 || s.PaneAt(3, 1) != -1 {
+		t.Error("invalid pane hit")
+	}
+	if s.Panes[0].TableRow(2) != -1 || s.Panes[0].TableRow(4) != 1 || s.Panes[1].Line(2) != 0 {
+		t.Error("invalid pane position")
+	}
+}
@@ -0,0 +1,70 @@
+package display
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type (
+	// Theme is the configurable part of the display styling
+	Theme struct {
+		// DueSoon is how far ahead a deadline is considered upcoming
+		DueSoon  time.Duration
+		Overdue  lipgloss.Style
+		DueToday lipgloss.Style
+		Upcoming lipgloss.Style
+		// Priorities are indexed by priority, unset levels are shown as numbers
+		Priorities []PriorityLevel
+	}
+
+	// PriorityLevel is how a priority is displayed
+	PriorityLevel struct {
+		Name  string
+		Style lipgloss.Style
+	}
+)
+
+// DefaultTheme will get the theme used unless configured
+func DefaultTheme() Theme {
+	return NewTheme(3*24*time.Hour, "", "", "")
+}
+
+// NewTheme will create a theme, empty colors are defaulted
+func NewTheme(soon time.Duration, overdue, today, upcoming string) Theme {
+	color := func(value string, fallback lipgloss.Color) lipgloss.Style {
+		c := fallback
+		if value != "" {
+			c = lipgloss.Color(value)
+		}
+		return lipgloss.NewStyle().Foreground(c)
+	}
+	return Theme{
+		DueSoon:  soon,
+		Overdue:  color(overdue, HighlightedBackgroundColor).Bold(true),
+		DueToday: color(today, taskSelectionColor),
+		Upcoming: color(upcoming, InputFormColor),
+	}
+}
+
+// NewPriorityLevel will create a priority level, an empty color is not tinted
+func NewPriorityLevel(name, color string) PriorityLevel {
+	style := lipgloss.NewStyle()
+	if color != "" {
+		style = style.Foreground(lipgloss.Color(color))
+	}
+	return PriorityLevel{Name: name, Style: style}
+}
+
+// Priority will get how a priority is displayed
+func (t Theme) Priority(priority uint64) PriorityLevel {
+	var level PriorityLevel
+	if priority < uint64(len(t.Priorities)) {
+		level = t.Priorities[priority]
+	}
+	if level.Name == "" {
+		level.Name = fmt.Sprintf("%d", priority)
+	}
+	return level
+}
@@ -0,0 +1,113 @@
+// Package durations handles human-friendly durations (e.g. 7d, 1w2d, 1h30m)
+package durations
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// Parse will parse a duration, supporting days (d) and weeks (w) in addition to time.ParseDuration units
+func Parse(value string) (time.Duration, error) {
+	text := strings.TrimSpace(value)
+	if text == "" {
+		return 0, errors.New("empty duration")
+	}
+	negative := false
+	switch text[0] {
+	case '-':
+		negative = true
+		text = text[1:]
+	case '+':
+		text = text[1:]
+	}
+	var result time.Duration
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"w", week},
+		{"d", day},
+	} {
+		idx := strings.Index(text, unit.suffix)
+		if idx < 0 {
+			continue
+		}
+		count, err := strconv.ParseUint(text[:idx], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		result += time.Duration(count) * unit.size
+		text = text[idx+1:]
+	}
+	if text != "" {
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		if parsed < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		result += parsed
+	}
+	if negative {
+		result = -result
+	}
+	return result, nil
+}
+
+// Format will format a duration for display (minute precision, e.g. 1d2h30m)
+func Format(d time.Duration) string {
+	if d < 0 {
+		return "-" + Format(-d)
+	}
+	d = d.Round(time.Minute)
+	var b strings.Builder
+	if days := d / day; days > 0 {
+		fmt.Fprintf(&b, "%dd", days)
+		d -= days * day
+	}
+	if hours := d / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dh", hours)
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 || b.Len() == 0 {
+		fmt.Fprintf(&b, "%dm", minutes)
+	}
+	return b.String()
+}
+
+// ParseList will parse a comma separated list of (non-negative) durations
+func ParseList(value string) ([]time.Duration, error) {
+	var result []time.Duration
+	for item := range strings.SplitSeq(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		d, err := Parse(item)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("invalid duration: %s", item)
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// FormatList will format a set of durations as a comma separated list
+func FormatList(items []time.Duration) string {
+	var result []string
+	for _, d := range items {
+		result = append(result, Format(d))
+	}
+	return strings.Join(result, ", ")
+}
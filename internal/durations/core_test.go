@@ -0,0 +1,64 @@
+package durations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/durations"
+)
+
+func TestParse(t *testing.T) {
+	for value, expect := range map[string]time.Duration{
+		"7d":     7 * 24 * time.Hour,
+		"1w2d":   9 * 24 * time.Hour,
+		"+2d":    48 * time.Hour,
+		"-1d12h": -36 * time.Hour,
+		"1h30m":  90 * time.Minute,
+		" 45m ":  45 * time.Minute,
+	} {
+		d, err := durations.Parse(value)
+		if err != nil || d != expect {
+			t.Errorf("invalid parse: %s (%v, %v)", value, d, err)
+		}
+	}
+	for _, value := range []string{"", "d", "xd", "1x", "1d-2h", "--1d"} {
+		if _, err := durations.Parse(value); err == nil {
+			t.Errorf("should fail: %s", value)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for d, expect := range map[time.Duration]string{
+		0:                               "0m",
+		30 * time.Second:                "1m",
+		90 * time.Minute:                "1h30m",
+		26 * time.Hour:                  "1d2h",
+		-5 * time.Minute:                "-5m",
+		48*time.Hour + 3*time.Minute:    "2d3m",
+		2*time.Hour + 29*time.Second:    "2h",
+		time.Hour + 59*time.Minute + 59: "1h59m",
+	} {
+		if s := durations.Format(d); s != expect {
+			t.Errorf("invalid format: %v (%s != %s)", d, s, expect)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	items, err := durations.ParseList("1d, 1h,,30m")
+	if err != nil || len(items) != 3 || items[0] != 24*time.Hour || items[2] != 30*time.Minute {
+		t.Errorf("invalid list: %v (%v)", items, err)
+	}
+	if s := durations.FormatList(items); s != "1d, 1h, 30m" {
+		t.Errorf("invalid format: %s", s)
+	}
+	if items, err := durations.ParseList(" "); err != nil || len(items) != 0 {
+		t.Errorf("invalid empty list: %v (%v)", items, err)
+	}
+	for _, value := range []string{"1d, x", "-1h"} {
+		if _, err := durations.ParseList(value); err == nil {
+			t.Errorf("should fail: %s", value)
+		}
+	}
+}
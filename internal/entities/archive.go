@@ -0,0 +1,132 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+// FetchArchive will retrieve all archived stacks (at any depth, with their archived tasks)
+func FetchArchive(archive backend.Store) []Stack {
+	var stacks []Stack
+	for _, item := range backend.Flatten(archive.Get()) {
+		c, ok := item.Node.(Stack)
+		if !ok {
+			continue
+		}
+		c.Tasks = []Task{}
+		for _, t := range item.Children {
+			task, ok := t.Node.(Task)
+			if ok && !task.Trashed() {
+				c.Tasks = append(c.Tasks, task)
+			}
+		}
+		SortTasks(c.Tasks)
+		stacks = append(stacks, c)
+	}
+	SortStacks(stacks)
+	return stacks
+}
+
+// FinishedBefore will get the (live) tasks that were finished before a given time
+func FinishedBefore(store backend.Store, before time.Time) []Task {
+	var tasks []Task
+	for _, stack := range ListStacks(store) {
+		for _, task := range stack.Tasks {
+			if !task.Finished.IsZero() && task.Finished.Before(before) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks
+}
+
+// ArchiveStack will move a stack and all of its tasks (and nested stacks) into the archive, stopping running timers
+func ArchiveStack(live, archive backend.Store, stack Stack) {
+	item, ok := findStack(live, stack.ID)
+	if !ok {
+		return
+	}
+	if stopTimers(live, item, time.Now()) {
+		item, _ = findStack(live, stack.ID)
+	}
+	archive.Batch(func() {
+		copyStack(archive, parentIn(archive, stack.ParentID), item)
+	})
+	live.Remove(stack.ID)
+}
+
+// ArchiveTasks will move tasks into the archive (under their stack), stopping running timers
+func ArchiveTasks(live, archive backend.Store, tasks []Task) {
+	now := time.Now()
+	live.Batch(func() {
+		archive.Batch(func() {
+			for _, t := range tasks {
+				if t.Running() {
+					t.stopTimer(now)
+				}
+				move(live, archive, t)
+			}
+		})
+	})
+}
+
+// UnarchiveStack will move an archived stack and its tasks (and nested stacks) back
+func UnarchiveStack(live, archive backend.Store, stack Stack) {
+	item, ok := findStack(archive, stack.ID)
+	if !ok {
+		return
+	}
+	live.Batch(func() {
+		copyStack(live, parentIn(live, stack.ParentID), item)
+	})
+	archive.Remove(stack.ID)
+}
+
+// copyStack will copy a stack (unless it already exists) under a parent with all of its children
+func copyStack(to backend.Store, parent string, item backend.Data) {
+	stack := item.Node.(Stack)
+	if _, ok := findStack(to, stack.ID); !ok {
+		to.Nest(parent, stack.ID, stack)
+	}
+	for id, child := range item.Children {
+		if _, ok := child.Node.(Stack); ok {
+			copyStack(to, stack.ID, child)
+			continue
+		}
+		to.AddChild(stack.ID, id, child.Node)
+	}
+}
+
+// parentIn will get the parent to use in a store, top-level when the parent is not in the store
+func parentIn(store backend.Store, parent string) string {
+	if _, ok := findStack(store, parent); !ok {
+		return ""
+	}
+	return parent
+}
+
+// UnarchiveTask will move an archived task back (restoring its stack when needed)
+func UnarchiveTask(live, archive backend.Store, task Task) {
+	live.Batch(func() {
+		archive.Batch(func() {
+			move(archive, live, task)
+			if item, ok := findStack(archive, task.StackID); ok && len(item.Children) == 0 {
+				archive.Remove(task.StackID)
+			}
+		})
+	})
+}
+
+func move(from, to backend.Store, task Task) {
+	if _, ok := findStack(to, task.StackID); !ok {
+		item, ok := findStack(from, task.StackID)
+		if !ok {
+			return
+		}
+		stack := item.Node.(Stack)
+		to.Nest(parentIn(to, stack.ParentID), stack.ID, stack)
+	}
+	to.AddChild(task.StackID, task.ID, task)
+	from.RemoveChild(task.StackID, task.ID)
+}
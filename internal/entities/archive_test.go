@@ -0,0 +1,84 @@
+package entities_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func newArchiveStores() (*backend.MemoryBased, *backend.MemoryBased) {
+	var buf bytes.Buffer
+	live := backend.NewMemoryBased("", false, &buf)
+	archive := backend.NewMemoryBased("", false, &buf)
+	stack := entities.Stack{ID: "s", Title: "stack"}
+	stack.Save(live)
+	for _, id := range []string{"a", "b"} {
+		task := entities.Task{ID: id, Title: id, StackID: "s"}
+		if id == "a" {
+			task.Finished = time.Now().Add(-time.Hour)
+		}
+		task.Save(live)
+	}
+	return live, archive
+}
+
+func TestArchiveTasks(t *testing.T) {
+	live, archive := newArchiveStores()
+	tasks := entities.FinishedBefore(live, time.Now())
+	if len(tasks) != 1 || tasks[0].ID != "a" {
+		t.Errorf("invalid finished: %v", tasks)
+	}
+	if len(entities.FinishedBefore(live, time.Now().Add(-2*time.Hour))) != 0 {
+		t.Error("invalid finished")
+	}
+	entities.ArchiveTasks(live, archive, tasks)
+	stacks := entities.FetchStacks(live)
+	if len(stacks) != 1 || len(stacks[0].Tasks) != 1 || stacks[0].Tasks[0].ID != "b" {
+		t.Errorf("invalid live: %v", stacks)
+	}
+	archived := entities.FetchArchive(archive)
+	if len(archived) != 1 || archived[0].Title != "stack" || len(archived[0].Tasks) != 1 {
+		t.Errorf("invalid archive: %v", archived)
+	}
+	entities.UnarchiveTask(live, archive, archived[0].Tasks[0])
+	if len(entities.FetchArchive(archive)) != 0 {
+		t.Error("archive should be empty")
+	}
+	if stacks := entities.FetchStacks(live); len(stacks[0].Tasks) != 2 {
+		t.Errorf("task not restored: %v", stacks)
+	}
+	if live.Errored() || archive.Errored() {
+		t.Error("unexpected errors")
+	}
+}
+
+func TestArchiveStack(t *testing.T) {
+	live, archive := newArchiveStores()
+	stack := entities.FetchStacks(live)[0]
+	entities.ArchiveStack(live, archive, stack)
+	if len(live.Get()) != 0 {
+		t.Error("stack not archived")
+	}
+	archived := entities.FetchArchive(archive)
+	if len(archived) != 1 || len(archived[0].Tasks) != 2 {
+		t.Errorf("invalid archive: %v", archived)
+	}
+	entities.UnarchiveTask(live, archive, archived[0].Tasks[0])
+	stacks := entities.FetchStacks(live)
+	if len(stacks) != 1 || stacks[0].Title != "stack" || len(stacks[0].Tasks) != 1 {
+		t.Errorf("stack not restored with task: %v", stacks)
+	}
+	entities.UnarchiveStack(live, archive, archived[0])
+	if len(entities.FetchArchive(archive)) != 0 {
+		t.Error("archive should be empty")
+	}
+	if stacks := entities.FetchStacks(live); len(stacks) != 1 || len(stacks[0].Tasks) != 2 {
+		t.Errorf("stack not restored: %v", stacks)
+	}
+	if live.Errored() || archive.Errored() {
+		t.Error("unexpected errors")
+	}
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/google/uuid"
+)
+
+// CloneOptions change what is copied when cloning a stack
+type CloneOptions struct {
+	// ResetFinished will clone finished tasks as unfinished
+	ResetFinished bool
+	// Shift moves (set) deadlines by an offset
+	Shift time.Duration
+}
+
+// clone will copy a task (new ID, no history or tracked time) into a stack
+func (t Task) clone(stackID string) Task {
+	c := NewTask()
+	c.Title = t.Title
+	c.Notes = t.Notes
+	c.Deadline = t.Deadline
+	c.Priority = t.Priority
+	c.Finished = t.Finished
+	c.Estimate = t.Estimate
+	c.Reminders = slices.Clone(t.Reminders)
+	c.StackID = stackID
+	return c
+}
+
+// CloneTasks will copy tasks (unfinished) into a stack
+func CloneTasks(store backend.Store, tasks []Task, stackID string) []Task {
+	var cloned []Task
+	store.Batch(func() {
+		for _, task := range tasks {
+			c := task.clone(stackID)
+			c.Finished = time.Time{}
+			cloned = append(cloned, c.Save(store).(Task))
+		}
+	})
+	return cloned
+}
+
+// CloneStack will copy a stack and its tasks (not nested stacks), next to the stack
+func CloneStack(store backend.Store, stack Stack, opts CloneOptions) Stack {
+	c := Stack{
+		ID:       uuid.NewString(),
+		Title:    stack.Title + " (copy)",
+		ParentID: stack.ParentID,
+		Sort:     stack.Sort,
+	}
+	store.Batch(func() {
+		c = c.Save(store).(Stack)
+		for _, task := range stack.Tasks {
+			cloned := task.clone(c.ID)
+			cloned.Ordinal = task.Ordinal
+			if opts.ResetFinished {
+				cloned.Finished = time.Time{}
+			}
+			if !cloned.Deadline.IsZero() {
+				cloned.Deadline = cloned.Deadline.Add(opts.Shift)
+			}
+			cloned.Save(store)
+		}
+	})
+	if saved, ok := FindStack(store, c.ID); ok {
+		c = saved
+	}
+	return c
+}
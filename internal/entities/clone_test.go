@@ -0,0 +1,93 @@
+package entities_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func TestCloneTasks(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	for _, id := range []string{"s", "o"} {
+		stack := entities.Stack{ID: id, Title: id}
+		stack.Save(m)
+	}
+	now := time.Now()
+	task := entities.Task{ID: "a", Title: "a", Notes: "n", StackID: "s", Priority: 3, Deadline: now, Finished: now, Estimate: time.Hour, Reminders: []time.Duration{time.Hour}}
+	task.Time = []entities.TimeEntry{{Start: now, Stop: now.Add(time.Minute)}}
+	task.Save(m)
+	cloned := entities.CloneTasks(m, []entities.Task{task}, "o")
+	if len(cloned) != 1 {
+		t.Fatalf("invalid clone: %v", cloned)
+	}
+	c := cloned[0]
+	if c.ID == task.ID || c.StackID != "o" || c.Title != "a" || c.Notes != "n" || c.Priority != 3 || !c.Deadline.Equal(now) || c.Estimate != time.Hour || len(c.Reminders) != 1 {
+		t.Errorf("invalid clone: %v", c)
+	}
+	if !c.Finished.IsZero() || len(c.Time) != 0 || len(c.History) != 0 {
+		t.Errorf("clone should be unfinished/untracked: %v", c)
+	}
+	if stack, _ := entities.FindStack(m, "s"); len(stack.Tasks) != 1 {
+		t.Errorf("original should remain: %v", stack.Tasks)
+	}
+	if stack, _ := entities.FindStack(m, "o"); len(stack.Tasks) != 1 || stack.Tasks[0].ID != c.ID {
+		t.Errorf("invalid stack: %v", stack.Tasks)
+	}
+}
+
+func TestCloneStack(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	parent := entities.Stack{ID: "p", Title: "p"}
+	parent.Save(m)
+	stack := entities.Stack{ID: "s", Title: "s", ParentID: "p", Sort: entities.SortManual}
+	stack.Save(m)
+	nested := entities.Stack{ID: "n", Title: "n", ParentID: "s"}
+	nested.Save(m)
+	now := time.Now()
+	for _, task := range []entities.Task{
+		{ID: "a", Title: "a", StackID: "s", Deadline: now, Ordinal: 2},
+		{ID: "b", Title: "b", StackID: "s", Finished: now, Ordinal: 1},
+		{ID: "c", Title: "c", StackID: "n"},
+	} {
+		task.Save(m)
+	}
+	stack, _ = entities.FindStack(m, "s")
+	c := entities.CloneStack(m, stack, entities.CloneOptions{})
+	if c.ID == stack.ID || c.Title != "s (copy)" || c.ParentID != "p" || c.Sort != entities.SortManual || len(c.Tasks) != 2 {
+		t.Fatalf("invalid clone: %v", c)
+	}
+	for _, task := range c.Tasks {
+		switch task.Title {
+		case "a":
+			if !task.Deadline.Equal(now) || task.Ordinal != 2 || task.ID == "a" {
+				t.Errorf("invalid task: %v", task)
+			}
+		case "b":
+			if task.Finished.IsZero() || task.Ordinal != 1 {
+				t.Errorf("finished should be kept: %v", task)
+			}
+		default:
+			t.Errorf("unexpected task: %v", task)
+		}
+	}
+	c = entities.CloneStack(m, stack, entities.CloneOptions{ResetFinished: true, Shift: 24 * time.Hour})
+	for _, task := range c.Tasks {
+		if !task.Finished.IsZero() {
+			t.Errorf("finished should be reset: %v", task)
+		}
+		if task.Title == "a" && !task.Deadline.Equal(now.Add(24*time.Hour)) {
+			t.Errorf("deadline should be shifted: %v", task)
+		}
+		if task.Title == "b" && !task.Deadline.IsZero() {
+			t.Errorf("no deadline should stay unset: %v", task)
+		}
+	}
+	if len(entities.ListStacks(m)) != 5 {
+		t.Errorf("invalid stacks: %v", entities.ListStacks(m))
+	}
+}
@@ -2,6 +2,9 @@
 package entities
 
 import (
+	"slices"
+	"time"
+
 	"github.com/enckse/mayhem/internal/backend"
 )
 
@@ -11,8 +14,51 @@ type (
 		Save(backend.Store) Entity
 		Delete(backend.Store)
 	}
+	// Timestamps track when an entity was created and last updated
+	Timestamps struct {
+		Created time.Time
+		Updated time.Time
+	}
+	// Timestamped are entities with created/updated times
+	Timestamped interface {
+		Stamps() Timestamps
+	}
 )
 
+// Stamps will get the entity timestamps
+func (t Timestamps) Stamps() Timestamps {
+	return t
+}
+
+func (t *Timestamps) touch(now time.Time) {
+	if t.Created.IsZero() {
+		t.Created = now
+	}
+	t.Updated = now
+}
+
+// SortByCreated will sort entities by creation time (oldest first)
+func SortByCreated[T Timestamped](items []T) {
+	slices.SortStableFunc(items, func(x, y T) int {
+		return x.Stamps().Created.Compare(y.Stamps().Created)
+	})
+}
+
+// SortByUpdated will sort entities by update time (most recent first)
+func SortByUpdated[T Timestamped](items []T) {
+	slices.SortStableFunc(items, func(x, y T) int {
+		return y.Stamps().Updated.Compare(x.Stamps().Updated)
+	})
+}
+
+// FilterByTimestamps will keep the entities created and updated after times (a zero time does not filter)
+func FilterByTimestamps[T Timestamped](items []T, createdAfter, updatedAfter time.Time) []T {
+	return slices.DeleteFunc(slices.Clone(items), func(item T) bool {
+		stamps := item.Stamps()
+		return stamps.Created.Before(createdAfter) || stamps.Updated.Before(updatedAfter)
+	})
+}
+
 // FindByIndex will find an entity by an id (from a set)
 func FindByIndex[T interface{ EntityID() string }](arr []T, id string) int {
 	for i, val := range arr {
@@ -1,7 +1,11 @@
 package entities_test
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/enckse/mayhem/internal/backend"
 	"github.com/enckse/mayhem/internal/entities"
@@ -17,6 +21,10 @@ func (m *mockDB) Add(_ string, obj any) {
 	m.last = obj
 }
 
+func (m *mockDB) Nest(_, _ string, obj any) {
+	m.last = obj
+}
+
 func (m *mockDB) AddChild(_, _ string, obj any) {
 	m.last = obj
 }
@@ -41,6 +49,10 @@ func (m *mockDB) Log(cat string, _ error) {
 	m.cat = cat
 }
 
+func (m *mockDB) Batch(changes func()) {
+	changes()
+}
+
 func TestFindByIndex(t *testing.T) {
 	idx := entities.FindByIndex([]entities.Stack{}, "")
 	if idx != -1 {
@@ -55,3 +67,55 @@ func TestFindByIndex(t *testing.T) {
 		t.Errorf("invalid index: %d", idx)
 	}
 }
+
+func TestOpenStore(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := entities.OpenStore("", false, nil, &buf); err != nil {
+		t.Errorf("invalid open: %v", err)
+	}
+	os.MkdirAll("testdata", os.ModePerm)
+	file := filepath.Join("testdata", "open.json")
+	os.Remove(file)
+	s, err := entities.OpenStore(file, false, nil, &buf)
+	if err != nil {
+		t.Errorf("invalid open: %v", err)
+	}
+	stack := entities.Stack{ID: "x", Title: "x"}
+	stack.Save(s)
+	if s, err = entities.OpenStore(file, false, nil, &buf); err != nil || len(entities.FetchStacks(s)) != 1 {
+		t.Errorf("invalid load: %v", err)
+	}
+	os.WriteFile(file, []byte("{"), 0o644)
+	if _, err := entities.OpenStore(file, false, nil, &buf); err == nil {
+		t.Error("invalid load should fail")
+	}
+}
+
+func TestSortByTimestamps(t *testing.T) {
+	now := time.Now()
+	s := []entities.Task{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+	s[0].Created, s[0].Updated = now, now
+	s[1].Created, s[1].Updated = now.Add(-time.Hour), now.Add(time.Hour)
+	s[2].Updated = now.Add(-time.Hour)
+	entities.SortByCreated(s)
+	if s[0].Title != "c" || s[1].Title != "b" || s[2].Title != "a" {
+		t.Errorf("invalid sort: %v", s)
+	}
+	entities.SortByUpdated(s)
+	if s[0].Title != "b" || s[1].Title != "a" || s[2].Title != "c" {
+		t.Errorf("invalid sort: %v", s)
+	}
+	stacks := []entities.Stack{{Title: "new"}, {Title: "old"}}
+	stacks[0].Created = now
+	stacks[1].Created = now.Add(-time.Hour)
+	entities.SortByCreated(stacks)
+	if stacks[0].Title != "old" {
+		t.Errorf("invalid sort: %v", stacks)
+	}
+	if recent := entities.FilterByTimestamps(s, time.Time{}, now); len(recent) != 2 || recent[0].Title != "b" || len(s) != 3 {
+		t.Errorf("invalid filter: %v", recent)
+	}
+	if created := entities.FilterByTimestamps(s, now.Add(-time.Minute), time.Time{}); len(created) != 1 || created[0].Title != "a" {
+		t.Errorf("invalid filter: %v", created)
+	}
+}
@@ -0,0 +1,44 @@
+package entities
+
+const (
+	// TaskAdd is when a task is created
+	TaskAdd = "task.add"
+	// TaskUpdate is when a task is changed (but not finished/deleted)
+	TaskUpdate = "task.update"
+	// TaskFinish is when a task is finished
+	TaskFinish = "task.finish"
+	// TaskDelete is when a task is moved to the trash
+	TaskDelete = "task.delete"
+	// StackAdd is when a stack is created
+	StackAdd = "stack.add"
+	// StackUpdate is when a stack is changed (but not deleted)
+	StackUpdate = "stack.update"
+	// StackDelete is when a stack is moved to the trash
+	StackDelete = "stack.delete"
+)
+
+// StackEvent will get the event for a stack change (prev is the stack before the change, if any)
+func StackEvent(prev any, stack Stack) string {
+	old, exists := prev.(Stack)
+	switch {
+	case !exists:
+		return StackAdd
+	case stack.Trashed() && !old.Trashed():
+		return StackDelete
+	}
+	return StackUpdate
+}
+
+// TaskEvent will get the event for a task change (prev is the task before the change, if any)
+func TaskEvent(prev any, task Task) string {
+	old, exists := prev.(Task)
+	switch {
+	case !exists:
+		return TaskAdd
+	case task.Trashed() && !old.Trashed():
+		return TaskDelete
+	case !task.Finished.IsZero() && old.Finished.IsZero():
+		return TaskFinish
+	}
+	return TaskUpdate
+}
@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/durations"
+)
+
+const (
+	// maxHistory is how many changes are kept per task
+	maxHistory = 50
+	// maxHistoryValue limits the size of values recorded in history
+	maxHistoryValue = 60
+	historyTime     = "2006-01-02 15:04"
+	historyDay      = "2006-01-02"
+)
+
+// Change is a recorded change to a task field
+type Change struct {
+	Field string
+	Old   string
+	New   string
+	Time  time.Time
+}
+
+func findTask(store backend.Store, id string) (Task, bool) {
+	for _, item := range backend.Flatten(store.Get()) {
+		if t, ok := item.Children[id]; ok {
+			if task, ok := t.Node.(Task); ok {
+				return task, true
+			}
+		}
+	}
+	return Task{}, false
+}
+
+func stackTitle(store backend.Store, id string) string {
+	if item, ok := findStack(store, id); ok {
+		return item.Node.(Stack).Title
+	}
+	return id
+}
+
+func historyValue(value string) string {
+	runes := []rune(value)
+	if len(runes) > maxHistoryValue {
+		return string(runes[:maxHistoryValue-1]) + "…"
+	}
+	return value
+}
+
+func historyTimeValue(value time.Time) string {
+	if value.IsZero() {
+		return "-"
+	}
+	return value.Format(historyTime)
+}
+
+func historyDayValue(value time.Time) string {
+	if value.IsZero() {
+		return "-"
+	}
+	return value.Format(historyDay)
+}
+
+// record will add the differences between the previous and current task to the task history
+func (t *Task) record(store backend.Store, prev Task, now time.Time) {
+	add := func(field, before, after string) {
+		if before == after {
+			return
+		}
+		t.History = append(t.History, Change{Field: field, Old: historyValue(before), New: historyValue(after), Time: now})
+	}
+	add("Title", prev.Title, t.Title)
+	add("Notes", prev.Notes, t.Notes)
+	add("Priority", fmt.Sprintf("%d", prev.Priority), fmt.Sprintf("%d", t.Priority))
+	add("Deadline", historyTimeValue(prev.Deadline), historyTimeValue(t.Deadline))
+	add("Finished", historyTimeValue(prev.Finished), historyTimeValue(t.Finished))
+	add("Estimate", durations.Format(prev.Estimate), durations.Format(t.Estimate))
+	add("Reminders", durations.FormatList(prev.Reminders), durations.FormatList(t.Reminders))
+	add("Planned", historyDayValue(prev.Planned), historyDayValue(t.Planned))
+	if prev.StackID != t.StackID {
+		add("Stack", stackTitle(store, prev.StackID), stackTitle(store, t.StackID))
+	}
+	if over := len(t.History) - maxHistory; over > 0 {
+		t.History = t.History[over:]
+	}
+}
@@ -0,0 +1,70 @@
+package entities_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func TestHistory(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	work := entities.Stack{ID: "work", Title: "Work"}
+	work.Save(m)
+	home := entities.Stack{ID: "home", Title: "Home"}
+	home.Save(m)
+	task := entities.Task{ID: "t", Title: "task", StackID: "work"}
+	task = task.Save(m).(entities.Task)
+	if len(task.History) != 0 || task.Created.IsZero() || task.Updated != task.Created {
+		t.Errorf("invalid new task: %v", task)
+	}
+	task.Priority = 2
+	task.Deadline = time.Date(2026, 1, 2, 3, 4, 0, 0, time.Local)
+	task.StackID = "home"
+	updated := task.Save(m).(entities.Task)
+	if len(updated.History) != 3 || updated.Updated.Before(task.Created) {
+		t.Errorf("invalid history: %v", updated.History)
+	}
+	for idx, expect := range []string{"Priority 0 2", "Deadline - 2026-01-02 03:04", "Stack Work Home"} {
+		change := updated.History[idx]
+		if got := strings.Join([]string{change.Field, change.Old, change.New}, " "); got != expect {
+			t.Errorf("invalid change: %s != %s", got, expect)
+		}
+	}
+	updated.Notes = strings.Repeat("x", 100)
+	updated = updated.Save(m).(entities.Task)
+	if last := updated.History[len(updated.History)-1]; len([]rune(last.New)) != 60 || !strings.HasSuffix(last.New, "…") {
+		t.Errorf("invalid truncation: %s", last.New)
+	}
+	for i := 0; i < 100; i++ {
+		updated.Priority = uint64(i % 2)
+		updated = updated.Save(m).(entities.Task)
+	}
+	if len(updated.History) != 50 {
+		t.Errorf("history not bounded: %d", len(updated.History))
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
+
+func TestHistoryStale(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	stack := entities.Stack{ID: "s", Title: "s"}
+	stack.Save(m)
+	task := entities.Task{ID: "t", Title: "task", StackID: "s"}
+	task.Save(m)
+	stale := task
+	task.Priority = 1
+	task.Save(m)
+	stale.Title = "renamed"
+	saved := stale.Save(m).(entities.Task)
+	if len(saved.History) != 3 || saved.Created.IsZero() {
+		t.Errorf("invalid history: %v", saved.History)
+	}
+}
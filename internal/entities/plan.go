@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+// Day will get the start of the day of a time
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// PlannedOn indicates the task is planned for the day of a time
+func (t Task) PlannedOn(day time.Time) bool {
+	return !t.Planned.IsZero() && Day(t.Planned).Equal(Day(day))
+}
+
+// PlannedTasks will get the tasks (finished or not) planned for the day of a time (sorted by default)
+func PlannedTasks(stacks []Stack, day time.Time) []Task {
+	var tasks []Task
+	for _, stack := range stacks {
+		for _, task := range stack.Tasks {
+			if task.PlannedOn(day) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	SortTasksBy(tasks, SortDefault)
+	return tasks
+}
+
+// RollOverTasks will get the unfinished tasks planned for a day before the day of a time
+func RollOverTasks(stacks []Stack, day time.Time) []Task {
+	var tasks []Task
+	for _, stack := range stacks {
+		for _, task := range stack.Tasks {
+			if task.Finished.IsZero() && !task.Planned.IsZero() && task.Planned.Before(Day(day)) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks
+}
+
+// PlanTasks will plan tasks for the day of a time (unplanning them when the time is zero)
+func PlanTasks(store backend.Store, tasks []Task, day time.Time) {
+	if !day.IsZero() {
+		day = Day(day)
+	}
+	store.Batch(func() {
+		for _, task := range tasks {
+			task.Planned = day
+			task.Save(store)
+		}
+	})
+}
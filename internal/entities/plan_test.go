@@ -0,0 +1,55 @@
+package entities_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func TestPlannedTasks(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	yesterday := now.AddDate(0, 0, -1)
+	stacks := []entities.Stack{
+		{Title: "a", Tasks: []entities.Task{
+			{Title: "later", Planned: entities.Day(now)},
+			{Title: "done", Planned: entities.Day(now), Finished: now},
+			{Title: "unplanned"},
+			{Title: "stale", Planned: entities.Day(yesterday)},
+			{Title: "stale done", Planned: entities.Day(yesterday), Finished: yesterday},
+		}},
+		{Title: "b", Tasks: []entities.Task{{Title: "due", Planned: now.Add(-time.Hour), Deadline: now}}},
+	}
+	planned := entities.PlannedTasks(stacks, now.Add(10*time.Hour))
+	if len(planned) != 3 || planned[0].Title != "due" || planned[1].Title != "later" || planned[2].Title != "done" {
+		t.Errorf("invalid planned tasks: %v", planned)
+	}
+	if stale := entities.RollOverTasks(stacks, now); len(stale) != 1 || stale[0].Title != "stale" {
+		t.Errorf("invalid roll over tasks: %v", stale)
+	}
+	if day := entities.Day(now); day.Hour() != 0 || day.Day() != 10 {
+		t.Errorf("invalid day: %v", day)
+	}
+}
+
+func TestPlanTasks(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	stack := entities.Stack{ID: "s", Title: "s"}
+	stack.Save(m)
+	task := entities.Task{ID: "a", Title: "a", StackID: "s"}
+	task.Save(m)
+	now := time.Now()
+	entities.PlanTasks(m, []entities.Task{task}, now)
+	stack, _ = entities.FindStack(m, "s")
+	if !stack.Tasks[0].PlannedOn(now) || !stack.Tasks[0].Planned.Equal(entities.Day(now)) || len(stack.Tasks[0].History) != 1 {
+		t.Errorf("should be planned: %v", stack.Tasks[0])
+	}
+	entities.PlanTasks(m, stack.Tasks, time.Time{})
+	stack, _ = entities.FindStack(m, "s")
+	if !stack.Tasks[0].Planned.IsZero() || stack.Tasks[0].PlannedOn(now) {
+		t.Errorf("should be unplanned: %v", stack.Tasks[0])
+	}
+}
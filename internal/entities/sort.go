@@ -0,0 +1,171 @@
+package entities
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+// SortStrategy is how the (unfinished) tasks of a stack are sorted, finished tasks are always last
+type SortStrategy string
+
+const (
+	// SortDefault sorts by deadline, then title
+	SortDefault SortStrategy = ""
+	// SortPriority sorts by priority (highest first), then deadline
+	SortPriority SortStrategy = "priority"
+	// SortDeadline sorts by deadline, then priority (highest first)
+	SortDeadline SortStrategy = "deadline"
+	// SortCreated sorts by creation time (oldest first)
+	SortCreated SortStrategy = "created"
+	// SortUpdated sorts by update time (most recent first)
+	SortUpdated SortStrategy = "updated"
+	// SortManual sorts by ordinal (as moved by the user)
+	SortManual SortStrategy = "manual"
+)
+
+// SortStrategies are all strategies (in the order they are cycled)
+var SortStrategies = []SortStrategy{SortDefault, SortPriority, SortDeadline, SortCreated, SortUpdated, SortManual}
+
+// String will get the display name of the strategy
+func (s SortStrategy) String() string {
+	if s == SortDefault {
+		return "default"
+	}
+	return string(s)
+}
+
+// Next will get the next strategy (when cycling)
+func (s SortStrategy) Next() SortStrategy {
+	idx := slices.Index(SortStrategies, s)
+	return SortStrategies[(idx+1)%len(SortStrategies)]
+}
+
+// SortTasksBy will sort unfinished tasks by strategy, followed by finished tasks (by finish time)
+func SortTasksBy(t []Task, strategy SortStrategy) {
+	slices.SortFunc(t, func(x, y Task) int {
+		if !x.Finished.IsZero() && y.Finished.IsZero() {
+			return 1
+		}
+		if x.Finished.IsZero() && !y.Finished.IsZero() {
+			return -1
+		}
+		if val := x.Finished.Compare(y.Finished); val != 0 {
+			return val
+		}
+		var val int
+		switch strategy {
+		case SortPriority:
+			val = cmp.Or(cmp.Compare(y.Priority, x.Priority), compareDeadlines(x, y))
+		case SortDeadline:
+			val = cmp.Or(compareDeadlines(x, y), cmp.Compare(y.Priority, x.Priority))
+		case SortCreated:
+			val = x.Created.Compare(y.Created)
+		case SortUpdated:
+			val = y.Updated.Compare(x.Updated)
+		case SortManual:
+			val = cmp.Or(compareOrdinals(x.Ordinal, y.Ordinal), x.Created.Compare(y.Created))
+		default:
+			val = compareDeadlines(x, y)
+		}
+		if val != 0 {
+			return val
+		}
+		return strings.Compare(x.Title, y.Title)
+	})
+}
+
+// tasks without deadlines are last
+func compareDeadlines(x, y Task) int {
+	if x.Deadline.IsZero() && !y.Deadline.IsZero() {
+		return 1
+	}
+	if !x.Deadline.IsZero() && y.Deadline.IsZero() {
+		return -1
+	}
+	return x.Deadline.Compare(y.Deadline)
+}
+
+// unordered (zero) ordinals are last
+func compareOrdinals(x, y int) int {
+	if x == 0 && y != 0 {
+		return 1
+	}
+	if x != 0 && y == 0 {
+		return -1
+	}
+	return cmp.Compare(x, y)
+}
+
+// MoveStack will move a stack up/down (by delta) in the (sorted) stacks, all stacks are (re)numbered
+func MoveStack(store backend.Store, stacks []Stack, idx, delta int) bool {
+	changed, ok := reorder(stacks, idx, delta, func(s *Stack, ordinal int) bool {
+		if s.Ordinal == ordinal {
+			return false
+		}
+		s.Ordinal = ordinal
+		return true
+	})
+	if ok {
+		store.Batch(func() {
+			for _, stack := range changed {
+				stack.Save(store)
+			}
+		})
+	}
+	return ok
+}
+
+// MoveTask will move an unfinished task up/down (by delta) in the (sorted) tasks of a stack,
+// the stack is switched to manual sorting and the unfinished tasks are (re)numbered
+func MoveTask(store backend.Store, stack Stack, tasks []Task, idx, delta int) bool {
+	open := slices.IndexFunc(tasks, func(t Task) bool {
+		return !t.Finished.IsZero()
+	})
+	if open < 0 {
+		open = len(tasks)
+	}
+	changed, ok := reorder(tasks[:open], idx, delta, func(t *Task, ordinal int) bool {
+		if t.Ordinal == ordinal {
+			return false
+		}
+		t.Ordinal = ordinal
+		return true
+	})
+	if ok {
+		store.Batch(func() {
+			for _, task := range changed {
+				task.Save(store)
+			}
+			if stack.Sort != SortManual {
+				stack.Sort = SortManual
+				stack.Save(store)
+			}
+		})
+	}
+	return ok
+}
+
+// reorder will move an item (by delta) and number all items (from 1), getting the changed items
+func reorder[T any](items []T, idx, delta int, set func(*T, int) bool) ([]T, bool) {
+	target := idx + delta
+	if idx < 0 || idx >= len(items) || target < 0 || target >= len(items) || delta == 0 {
+		return nil, false
+	}
+	item := items[idx]
+	if target > idx {
+		copy(items[idx:target], items[idx+1:target+1])
+	} else {
+		copy(items[target+1:idx+1], items[target:idx])
+	}
+	items[target] = item
+	var changed []T
+	for i := range items {
+		if set(&items[i], i+1) {
+			changed = append(changed, items[i])
+		}
+	}
+	return changed, true
+}
@@ -0,0 +1,119 @@
+package entities_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func titles(tasks []entities.Task) string {
+	var result []string
+	for _, t := range tasks {
+		result = append(result, t.Title)
+	}
+	return strings.Join(result, ",")
+}
+
+func TestSortTasksBy(t *testing.T) {
+	now := time.Now()
+	tasks := []entities.Task{
+		{Title: "a", Priority: 1, Deadline: now.Add(2 * time.Hour), Ordinal: 2},
+		{Title: "b", Priority: 3, Timestamps: entities.Timestamps{Created: now.Add(-time.Hour)}},
+		{Title: "c", Priority: 1, Deadline: now.Add(time.Hour), Timestamps: entities.Timestamps{Created: now}, Ordinal: 1},
+		{Title: "d", Priority: 3, Deadline: now.Add(2 * time.Hour), Timestamps: entities.Timestamps{Created: now.Add(-2 * time.Hour)}},
+		{Title: "e", Priority: 4, Finished: now},
+	}
+	tasks[0].Updated = now.Add(time.Hour)
+	tasks[2].Updated = now
+	tasks[3].Updated = now.Add(-time.Minute)
+	for strategy, expect := range map[entities.SortStrategy]string{
+		entities.SortDefault:  "c,a,d,b,e",
+		entities.SortPriority: "d,b,c,a,e",
+		entities.SortDeadline: "c,d,a,b,e",
+		entities.SortCreated:  "a,d,b,c,e",
+		entities.SortUpdated:  "a,c,d,b,e",
+		entities.SortManual:   "c,a,d,b,e",
+	} {
+		entities.SortTasksBy(tasks, strategy)
+		if result := titles(tasks); result != expect {
+			t.Errorf("invalid %s sort: %s", strategy, result)
+		}
+	}
+}
+
+func TestSortStrategyNext(t *testing.T) {
+	strategy := entities.SortDefault
+	var seen []string
+	for range entities.SortStrategies {
+		seen = append(seen, strategy.String())
+		strategy = strategy.Next()
+	}
+	if strategy != entities.SortDefault || strings.Join(seen, ",") != "default,priority,deadline,created,updated,manual" {
+		t.Errorf("invalid cycle: %v", seen)
+	}
+}
+
+func TestMoveStack(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	for _, title := range []string{"a", "b", "c"} {
+		entities.Stack{ID: title, Title: title}.Save(m)
+	}
+	stacks := entities.ListStacks(m)
+	entities.SortStacks(stacks)
+	if entities.MoveStack(m, stacks, 0, -1) || entities.MoveStack(m, stacks, 2, 1) {
+		t.Error("invalid move")
+	}
+	if !entities.MoveStack(m, stacks, 2, -1) {
+		t.Error("move failed")
+	}
+	stacks = entities.ListStacks(m)
+	entities.SortStacks(stacks)
+	var result []string
+	for _, s := range stacks {
+		result = append(result, s.Title)
+	}
+	if strings.Join(result, ",") != "a,c,b" {
+		t.Errorf("invalid order: %v", result)
+	}
+	entities.Stack{ID: "new", Title: "0"}.Save(m)
+	stacks = entities.ListStacks(m)
+	entities.SortStacks(stacks)
+	if stacks[3].ID != "new" {
+		t.Errorf("unordered stacks should be last: %v", stacks)
+	}
+}
+
+func TestMoveTask(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	stack := entities.Stack{ID: "s", Title: "s"}
+	stack.Save(m)
+	for _, title := range []string{"a", "b", "c"} {
+		entities.Task{ID: title, Title: title, StackID: "s"}.Save(m)
+	}
+	entities.Task{ID: "d", Title: "d", StackID: "s", Finished: time.Now()}.Save(m)
+	stack, _ = entities.FindStack(m, "s")
+	entities.SortTasksBy(stack.Tasks, stack.Sort)
+	if entities.MoveTask(m, stack, stack.Tasks, 2, 1) || entities.MoveTask(m, stack, stack.Tasks, 3, -1) {
+		t.Error("finished tasks can not be moved")
+	}
+	if !entities.MoveTask(m, stack, stack.Tasks, 0, 2) {
+		t.Error("move failed")
+	}
+	stack, _ = entities.FindStack(m, "s")
+	if stack.Sort != entities.SortManual {
+		t.Errorf("stack should be manually sorted: %v", stack.Sort)
+	}
+	entities.SortTasksBy(stack.Tasks, stack.Sort)
+	if result := titles(stack.Tasks); result != "b,c,a,d" {
+		t.Errorf("invalid order: %s", result)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
@@ -4,16 +4,25 @@ import (
 	"errors"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/enckse/mayhem/internal/backend"
 	"github.com/google/uuid"
 )
 
-// Stack is a set of tasks, sorted alphabetically
+// Stack is a set of tasks (and nested stacks), sorted manually (by ordinal) then alphabetically
 type Stack struct {
-	ID    string
-	Title string
+	ID      string
+	Title   string
+	Deleted time.Time
+	// ParentID is the stack this stack is nested under (empty for top-level stacks)
+	ParentID string `json:",omitempty"`
+	Timestamps
 	Tasks []Task `json:"-"`
+	// Ordinal is the position of the stack when manually ordered (unordered stacks are last)
+	Ordinal int `json:",omitempty"`
+	// Sort is how the tasks of the stack are sorted
+	Sort SortStrategy `json:",omitempty"`
 }
 
 // OpenTasks will get the count of unfinished tasks
@@ -28,32 +37,29 @@ func (s Stack) OpenTasks() uint64 {
 	return count
 }
 
+// OverdueTasks will get the count of unfinished tasks past their deadline
+func (s Stack) OverdueTasks(now time.Time) uint64 {
+	var count uint64
+	for _, t := range s.Tasks {
+		if t.Due(now, 0) == Overdue {
+			count++
+		}
+	}
+	return count
+}
+
 // NewStack will create a new stack
 func NewStack(store backend.Store) Stack {
 	stack := Stack{Title: "New Stack"}
 	stack.ID = uuid.NewString()
+	stack.touch(time.Now())
 	store.Add(stack.ID, stack)
 	return stack
 }
 
-// FetchStacks will retrieve all stacks
+// FetchStacks will retrieve all stacks (creating a new stack when there are none)
 func FetchStacks(store backend.Store) []Stack {
-	var stacks []Stack
-	for _, item := range store.Get() {
-		c, ok := item.Node.(Stack)
-		if !ok {
-			continue
-		}
-		c.Tasks = []Task{}
-		for _, t := range item.Children {
-			task, ok := t.Node.(Task)
-			if ok {
-				c.Tasks = append(c.Tasks, task)
-			}
-		}
-		stacks = append(stacks, c)
-	}
-
+	stacks := ListStacks(store)
 	if len(stacks) == 0 {
 		stack := NewStack(store)
 		return []Stack{stack}
@@ -62,29 +68,109 @@ func FetchStacks(store backend.Store) []Stack {
 	return stacks
 }
 
+// ListStacks will retrieve all stacks (at any depth)
+func ListStacks(store backend.Store) []Stack {
+	var stacks []Stack
+	var walk func(string, []backend.Data)
+	walk = func(parent string, items []backend.Data) {
+		for _, item := range items {
+			c, ok := item.Node.(Stack)
+			if !ok || c.Trashed() {
+				continue
+			}
+			c.ParentID = parent
+			c.Tasks = []Task{}
+			var nested []backend.Data
+			for _, t := range item.Children {
+				switch node := t.Node.(type) {
+				case Stack:
+					nested = append(nested, t)
+				case Task:
+					if !node.Trashed() {
+						c.Tasks = append(c.Tasks, node)
+					}
+				}
+			}
+			stacks = append(stacks, c)
+			walk(c.ID, nested)
+		}
+	}
+	walk("", store.Get())
+	return stacks
+}
+
+// FindStack will find a stack (that is not in the trash) by id
+func FindStack(store backend.Store, id string) (Stack, bool) {
+	stacks := ListStacks(store)
+	if idx := FindByIndex(stacks, id); idx >= 0 {
+		return stacks[idx], true
+	}
+	return Stack{}, false
+}
+
 // EntityID will get the entity ID for the object
 func (s Stack) EntityID() string {
 	return s.ID
 }
 
+// Validate will check the stack can be saved
+func (s Stack) Validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return errors.New("no title")
+	}
+	if s.ParentID != "" && s.ParentID == s.ID {
+		return errors.New("stack can not be nested under itself")
+	}
+	return nil
+}
+
 // Save will save the entity
 func (s Stack) Save(store backend.Store) Entity {
-	if strings.TrimSpace(s.Title) == "" {
-		store.Log("stack", errors.New("no title"))
+	if err := s.Validate(); err != nil {
+		store.Log("stack", err)
 		return s
 	}
-	store.Add(s.ID, s)
+	s.touch(time.Now())
+	store.Nest(s.ParentID, s.ID, s)
 	return s
 }
 
-// Delete will remove the entity
+// Delete will move the entity (and implicitly its tasks and nested stacks) to the trash, stopping running timers
 func (s Stack) Delete(store backend.Store) {
+	s.Deleted = time.Now()
+	store.Batch(func() {
+		if item, ok := findStack(store, s.ID); ok {
+			stopTimers(store, item, s.Deleted)
+		}
+		store.Nest(s.ParentID, s.ID, s)
+	})
+}
+
+// Restore will bring the entity back from the trash (to the top-level if its parent is no longer available)
+func (s Stack) Restore(store backend.Store) {
+	s.Deleted = time.Time{}
+	if _, ok := FindStack(store, s.ParentID); !ok {
+		s.ParentID = ""
+	}
+	store.Nest(s.ParentID, s.ID, s)
+}
+
+// Purge will permanently remove the entity, all tasks and nested stacks
+func (s Stack) Purge(store backend.Store) {
 	store.Remove(s.ID)
 }
 
-// SortStacks will sort by title
+// Trashed indicates if the stack is in the trash
+func (s Stack) Trashed() bool {
+	return !s.Deleted.IsZero()
+}
+
+// SortStacks will sort by ordinal, then title
 func SortStacks(s []Stack) {
 	slices.SortFunc(s, func(x, y Stack) int {
+		if val := compareOrdinals(x.Ordinal, y.Ordinal); val != 0 {
+			return val
+		}
 		return strings.Compare(x.Title, y.Title)
 	})
 }
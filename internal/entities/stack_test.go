@@ -30,6 +30,8 @@ func TestFetchStacks(t *testing.T) {
 	m.data[2].Children = make(backend.Map)
 	m.data[2].Children["x"] = backend.Data{Node: 1}
 	m.data[2].Children["y"] = backend.Data{Node: entities.Task{}}
+	m.data[2].Children["z"] = backend.Data{Node: entities.Task{Deleted: time.Now()}}
+	m.data = append(m.data, backend.Data{Node: entities.Stack{ID: "z", Deleted: time.Now()}})
 	s = entities.FetchStacks(m)
 	if len(s) != 2 {
 		t.Error("invalid stacks")
@@ -57,9 +59,17 @@ func TestSaveDelete(t *testing.T) {
 		t.Error("no save")
 	}
 	s.Delete(m)
-	if m.last != nil {
+	if m.last == nil || !m.last.(entities.Stack).Trashed() {
 		t.Error("no delete")
 	}
+	s.Restore(m)
+	if m.last == nil || m.last.(entities.Stack).Trashed() {
+		t.Error("no restore")
+	}
+	s.Purge(m)
+	if m.last != nil {
+		t.Error("no purge")
+	}
 }
 
 func TestSortStacks(t *testing.T) {
@@ -68,6 +78,11 @@ func TestSortStacks(t *testing.T) {
 	if len(s) != 2 || s[0].Title != "1" || s[1].Title != "X" {
 		t.Errorf("invalid sort: %v", s)
 	}
+	s = append(s, entities.Stack{Title: "Z", Ordinal: 2}, entities.Stack{Title: "Y", Ordinal: 1})
+	entities.SortStacks(s)
+	if s[0].Title != "Y" || s[1].Title != "Z" || s[2].Title != "1" {
+		t.Errorf("invalid ordinal sort: %v", s)
+	}
 }
 
 func TestOpenTasks(t *testing.T) {
@@ -78,6 +93,15 @@ func TestOpenTasks(t *testing.T) {
 	}
 }
 
+func TestOverdueTasks(t *testing.T) {
+	now := time.Now()
+	s := entities.Stack{}
+	s.Tasks = []entities.Task{{Deadline: now.Add(-time.Hour)}, {Deadline: now.Add(time.Hour)}, {}, {Deadline: now.Add(-time.Hour), Finished: now}}
+	if s.OverdueTasks(now) != 1 {
+		t.Error("invalid overdue task count")
+	}
+}
+
 func TestStaskEntityID(t *testing.T) {
 	e := entities.Stack{}
 	e.ID = "1"
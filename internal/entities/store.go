@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+// OpenStore will create a store of stacks/tasks, loading the file when it exists (a nil cipher is unencrypted)
+func OpenStore(file string, pretty bool, cipher backend.Cipher, logger io.Writer) (*backend.MemoryBased, error) {
+	store := backend.NewMemoryBased(file, pretty, logger)
+	if cipher != nil {
+		store.SetCipher(cipher)
+	}
+	if file == "" {
+		return store, nil
+	}
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err := backend.Load[Stack, Task](store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// findStack will find a stack (at any depth, including deleted stacks)
+func findStack(store backend.Store, id string) (backend.Data, bool) {
+	for _, item := range backend.Flatten(store.Get()) {
+		if s, ok := item.Node.(Stack); ok && s.ID == id {
+			return item, true
+		}
+	}
+	return backend.Data{}, false
+}
@@ -3,7 +3,6 @@ package entities
 import (
 	"errors"
 	"fmt"
-	"slices"
 	"strings"
 	"time"
 
@@ -13,9 +12,26 @@ import (
 
 const maxPriority = 4
 
+// DeadlineStatus indicates how close an (unfinished) task is to its deadline
+type DeadlineStatus int
+
+const (
+	// NotDue is a finished task, a task without a deadline or one that isn't due soon
+	NotDue DeadlineStatus = iota
+	// DueSoon is a task due within the 'soon' window
+	DueSoon
+	// DueToday is a task due later today
+	DueToday
+	// Overdue is a task past its deadline
+	Overdue
+)
+
 // MaxPriority is the maximum allowed priority
 var MaxPriority = fmt.Sprintf("%d", maxPriority)
 
+// PriorityLevels is the number of allowed priorities (0 to MaxPriority)
+const PriorityLevels = maxPriority + 1
+
 // Task defines task-based entities for work
 type Task struct {
 	ID       string
@@ -25,6 +41,16 @@ type Task struct {
 	Priority uint64
 	Finished time.Time
 	StackID  string
+	Deleted  time.Time
+	Timestamps
+	History   []Change `json:",omitempty"`
+	Estimate  time.Duration
+	Time      []TimeEntry     `json:",omitempty"`
+	Reminders []time.Duration `json:",omitempty"`
+	// Ordinal is the position of the task when manually sorted
+	Ordinal int `json:",omitempty"`
+	// Planned is the day the task is planned for (the focus/today list)
+	Planned time.Time `json:",omitzero"`
 }
 
 // NewTask will create a new task
@@ -32,48 +58,111 @@ func NewTask() Task {
 	return Task{ID: uuid.NewString()}
 }
 
-// Save will store the task
-func (t Task) Save(store backend.Store) Entity {
+// Validate will check the task can be saved
+func (t Task) Validate() error {
 	if strings.TrimSpace(t.Title) == "" {
-		store.Log("task", errors.New("no title"))
-		return t
+		return errors.New("no title")
 	}
 	if t.Priority > maxPriority {
-		store.Log("task", errors.New("invalid priority"))
+		return errors.New("invalid priority")
+	}
+	return nil
+}
+
+// Save will store the task
+func (t Task) Save(store backend.Store) Entity {
+	if err := t.Validate(); err != nil {
+		store.Log("task", err)
 		return t
 	}
+	now := time.Now()
+	if !t.Finished.IsZero() && t.Running() {
+		// a finished task no longer tracks time
+		t.stopTimer(now)
+	}
+	if prev, ok := findTask(store, t.ID); ok {
+		// the stored task is the source of truth for history
+		t.Created = prev.Created
+		t.History = prev.History
+		t.record(store, prev, now)
+	}
+	t.touch(now)
 	store.AddChild(t.StackID, t.ID, t)
 	return t
 }
 
-// Delete will remove the task
+// Due will get the deadline status of the task (soon is how far ahead is considered due soon)
+func (t Task) Due(now time.Time, soon time.Duration) DeadlineStatus {
+	if t.Deadline.IsZero() || !t.Finished.IsZero() {
+		return NotDue
+	}
+	if t.Deadline.Before(now) {
+		return Overdue
+	}
+	year, month, day := now.Date()
+	if dy, dm, dd := t.Deadline.In(now.Location()).Date(); dy == year && dm == month && dd == day {
+		return DueToday
+	}
+	if t.Deadline.Sub(now) <= soon {
+		return DueSoon
+	}
+	return NotDue
+}
+
+// FindTask will find a task (that is not in the trash) by id
+func FindTask(store backend.Store, id string) (Task, bool) {
+	for _, stack := range ListStacks(store) {
+		if idx := FindByIndex(stack.Tasks, id); idx >= 0 {
+			return stack.Tasks[idx], true
+		}
+	}
+	return Task{}, false
+}
+
+// ToggleFinished will finish (stopping a running timer) or unfinish the task, touching its stack
+func (t Task) ToggleFinished(store backend.Store, now time.Time) Task {
+	if t.Finished.IsZero() {
+		t.Finished = now
+	} else {
+		t.Finished = time.Time{}
+	}
+	store.Batch(func() {
+		t = t.Save(store).(Task)
+		if stack, ok := FindStack(store, t.StackID); ok {
+			stack.Save(store)
+		}
+	})
+	return t
+}
+
+// Delete will move the task to the trash (stopping a running timer)
 func (t Task) Delete(store backend.Store) {
+	t.Deleted = time.Now()
+	if t.Running() {
+		t.stopTimer(t.Deleted)
+	}
+	store.AddChild(t.StackID, t.ID, t)
+}
+
+// Restore will bring the task back from the trash
+func (t Task) Restore(store backend.Store) {
+	t.Deleted = time.Time{}
+	store.AddChild(t.StackID, t.ID, t)
+}
+
+// Purge will permanently remove the task
+func (t Task) Purge(store backend.Store) {
 	store.RemoveChild(t.StackID, t.ID)
 }
 
+// Trashed indicates if the task is in the trash
+func (t Task) Trashed() bool {
+	return !t.Deleted.IsZero()
+}
+
 // SortTasks will sort by finished, deadline, title
 func SortTasks(t []Task) {
-	slices.SortFunc(t, func(x, y Task) int {
-		if !x.Finished.IsZero() && y.Finished.IsZero() {
-			return 1
-		}
-		if x.Finished.IsZero() && !y.Finished.IsZero() {
-			return -1
-		}
-		if val := x.Finished.Compare(y.Finished); val != 0 {
-			return val
-		}
-		if x.Deadline.IsZero() && !y.Deadline.IsZero() {
-			return 1
-		}
-		if !x.Deadline.IsZero() && y.Deadline.IsZero() {
-			return -1
-		}
-		if val := x.Deadline.Compare(y.Deadline); val != 0 {
-			return val
-		}
-		return strings.Compare(x.Title, y.Title)
-	})
+	SortTasksBy(t, SortDefault)
 }
 
 // EntityID will get the entity ID for the object
@@ -27,9 +27,17 @@ func TestSaveDeleteTask(t *testing.T) {
 		t.Error("no save")
 	}
 	s.Delete(m)
-	if m.last != nil {
+	if m.last == nil || !m.last.(entities.Task).Trashed() {
 		t.Error("no delete")
 	}
+	s.Restore(m)
+	if m.last == nil || m.last.(entities.Task).Trashed() {
+		t.Error("no restore")
+	}
+	s.Purge(m)
+	if m.last != nil {
+		t.Error("no purge")
+	}
 }
 
 func TestSortTasks(t *testing.T) {
@@ -47,3 +55,23 @@ func TestTaskEntityID(t *testing.T) {
 		t.Error("invalid id")
 	}
 }
+
+func TestTaskDue(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	soon := 3 * 24 * time.Hour
+	for expect, task := range map[entities.DeadlineStatus]entities.Task{
+		entities.NotDue:   {},
+		entities.Overdue:  {Deadline: now.Add(-time.Second)},
+		entities.DueToday: {Deadline: now.Add(11 * time.Hour)},
+		entities.DueSoon:  {Deadline: now.Add(12 * time.Hour)},
+	} {
+		if status := task.Due(now, soon); status != expect {
+			t.Errorf("invalid status: %v != %v", status, expect)
+		}
+	}
+	for _, task := range []entities.Task{{Deadline: now.Add(-time.Hour), Finished: now}, {Deadline: now.Add(4 * 24 * time.Hour)}} {
+		if status := task.Due(now, soon); status != entities.NotDue {
+			t.Errorf("should not be due: %v", status)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+// TimeEntry is a tracked interval of work on a task, Stop is unset while running
+type TimeEntry struct {
+	Start time.Time
+	Stop  time.Time
+}
+
+// Running indicates if the task has a running timer
+func (t Task) Running() bool {
+	return len(t.Time) > 0 && t.Time[len(t.Time)-1].Stop.IsZero()
+}
+
+// Tracked will get the time tracked for a task within [since, now] (zero since is all time)
+func (t Task) Tracked(since, now time.Time) time.Duration {
+	var total time.Duration
+	for _, entry := range t.Time {
+		start := entry.Start
+		if start.Before(since) {
+			start = since
+		}
+		stop := entry.Stop
+		if stop.IsZero() || stop.After(now) {
+			stop = now
+		}
+		if stop.After(start) {
+			total += stop.Sub(start)
+		}
+	}
+	return total
+}
+
+// RunningTask will find the task with a running timer (only one timer runs at a time)
+func RunningTask(store backend.Store) (Task, bool) {
+	running := runningTasks(store)
+	if len(running) == 0 {
+		return Task{}, false
+	}
+	return running[0], true
+}
+
+func runningTasks(store backend.Store) []Task {
+	var running []Task
+	for _, stack := range ListStacks(store) {
+		for _, task := range stack.Tasks {
+			if task.Running() {
+				running = append(running, task)
+			}
+		}
+	}
+	return running
+}
+
+// stopTimers will stop the running timers of the tasks of a stack (and its nested stacks), getting whether any were stopped
+func stopTimers(store backend.Store, item backend.Data, now time.Time) bool {
+	stopped := false
+	for id, child := range item.Children {
+		switch node := child.Node.(type) {
+		case Stack:
+			stopped = stopTimers(store, child, now) || stopped
+		case Task:
+			if node.Running() {
+				node.stopTimer(now)
+				store.AddChild(item.Node.(Stack).ID, id, node)
+				stopped = true
+			}
+		}
+	}
+	return stopped
+}
+
+func (t *Task) stopTimer(now time.Time) {
+	t.Time = slices.Clone(t.Time)
+	t.Time[len(t.Time)-1].Stop = now
+}
+
+// ToggleTimer will stop the task timer if running, otherwise it will stop any other running timer and start one for the task
+func ToggleTimer(store backend.Store, task Task, now time.Time) Task {
+	if stored, ok := findTask(store, task.ID); ok {
+		task = stored
+	}
+	store.Batch(func() {
+		if task.Running() {
+			task.stopTimer(now)
+			task = task.Save(store).(Task)
+			return
+		}
+		for _, running := range runningTasks(store) {
+			running.stopTimer(now)
+			running.Save(store)
+		}
+		task.Time = append(slices.Clone(task.Time), TimeEntry{Start: now})
+		task = task.Save(store).(Task)
+	})
+	return task
+}
@@ -0,0 +1,136 @@
+package entities_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func TestToggleTimer(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	stack := entities.Stack{ID: "s", Title: "s"}
+	stack.Save(m)
+	first := entities.Task{ID: "a", Title: "a", StackID: "s"}
+	first.Save(m)
+	second := entities.Task{ID: "b", Title: "b", StackID: "s"}
+	second.Save(m)
+	if _, ok := entities.RunningTask(m); ok {
+		t.Error("no timer should be running")
+	}
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local)
+	first = entities.ToggleTimer(m, first, now)
+	if !first.Running() {
+		t.Error("timer should be running")
+	}
+	second = entities.ToggleTimer(m, second, now.Add(time.Hour))
+	running, ok := entities.RunningTask(m)
+	if !ok || running.ID != "b" {
+		t.Errorf("invalid running task: %v", running)
+	}
+	for _, s := range entities.FetchStacks(m) {
+		for _, task := range s.Tasks {
+			if task.ID == "a" {
+				first = task
+			}
+		}
+	}
+	if first.Running() || first.Tracked(time.Time{}, now.Add(2*time.Hour)) != time.Hour {
+		t.Errorf("first timer not stopped: %v", first.Time)
+	}
+	second = entities.ToggleTimer(m, second, now.Add(90*time.Minute))
+	if second.Running() || second.Tracked(time.Time{}, now.Add(2*time.Hour)) != 30*time.Minute {
+		t.Errorf("second timer not stopped: %v", second.Time)
+	}
+	if _, ok := entities.RunningTask(m); ok {
+		t.Error("no timer should be running")
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
+
+func TestTracked(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	task := entities.Task{Time: []entities.TimeEntry{
+		{Start: now.Add(-5 * time.Hour), Stop: now.Add(-3 * time.Hour)},
+		{Start: now.Add(-time.Hour)},
+	}}
+	if !task.Running() {
+		t.Error("task should be running")
+	}
+	if d := task.Tracked(time.Time{}, now); d != 3*time.Hour {
+		t.Errorf("invalid tracked: %v", d)
+	}
+	if d := task.Tracked(now.Add(-4*time.Hour), now); d != 2*time.Hour {
+		t.Errorf("invalid tracked since: %v", d)
+	}
+}
+
+func TestTimerStops(t *testing.T) {
+	setup := func() (backend.Store, backend.Store) {
+		var buf bytes.Buffer
+		live := backend.NewMemoryBased("", false, &buf)
+		archive := backend.NewMemoryBased("", false, &buf)
+		for _, stack := range []entities.Stack{{ID: "s", Title: "s"}, {ID: "n", Title: "n", ParentID: "s"}} {
+			stack.Save(live)
+		}
+		for _, task := range []entities.Task{{ID: "a", Title: "a", StackID: "s"}, {ID: "b", Title: "b", StackID: "n"}} {
+			task.Save(live)
+		}
+		return live, archive
+	}
+	start := func(store backend.Store, id string) entities.Task {
+		task, _ := entities.FindTask(store, id)
+		return entities.ToggleTimer(store, task, time.Now().Add(-time.Hour))
+	}
+	stopped := func(name string, store backend.Store, id string) {
+		for _, item := range backend.Flatten(store.Get()) {
+			if child, ok := item.Children[id]; ok {
+				if task := child.Node.(entities.Task); task.Running() || len(task.Time) != 1 {
+					t.Errorf("%s: timer should be stopped: %v", name, task)
+				}
+				return
+			}
+		}
+		t.Errorf("%s: task not found", name)
+	}
+
+	live, _ := setup()
+	start(live, "a").ToggleFinished(live, time.Now())
+	stopped("finish", live, "a")
+
+	live, _ = setup()
+	task := start(live, "b")
+	live.Batch(func() {
+		task.Finished = time.Now()
+		task.Save(live)
+	})
+	stopped("bulk finish", live, "b")
+
+	live, _ = setup()
+	start(live, "a").Delete(live)
+	stopped("trash task", live, "a")
+
+	live, _ = setup()
+	start(live, "b")
+	stack, _ := entities.FindStack(live, "s")
+	stack.Delete(live)
+	stopped("trash stack", live, "b")
+
+	live, archive := setup()
+	entities.ArchiveTasks(live, archive, []entities.Task{start(live, "a")})
+	stopped("archive task", archive, "a")
+
+	live, archive = setup()
+	start(live, "b")
+	stack, _ = entities.FindStack(live, "s")
+	entities.ArchiveStack(live, archive, stack)
+	stopped("archive stack", archive, "b")
+	if archived := entities.FetchArchive(archive); len(archived) != 2 {
+		t.Errorf("invalid archive: %v", archived)
+	}
+}
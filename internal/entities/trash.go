@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+)
+
+// Trash is the set of deleted (but not purged) entities
+type Trash struct {
+	// Stacks are deleted stacks (with all of their tasks)
+	Stacks []Stack
+	// Tasks are deleted tasks, their stack may also be deleted
+	Tasks []Task
+	// Available are the stacks (not deleted) that tasks can be restored to
+	Available []Stack
+}
+
+// FetchTrash will retrieve all trashed entities, most recently deleted first
+func FetchTrash(store backend.Store) Trash {
+	var trash Trash
+	var walk func(string, []backend.Data, bool)
+	// stacks nested under a deleted stack are implicitly deleted (and not available)
+	walk = func(parent string, items []backend.Data, deleted bool) {
+		for _, item := range items {
+			c, ok := item.Node.(Stack)
+			if !ok {
+				continue
+			}
+			c.ParentID = parent
+			c.Tasks = []Task{}
+			var nested []backend.Data
+			for _, t := range item.Children {
+				switch task := t.Node.(type) {
+				case Stack:
+					nested = append(nested, t)
+				case Task:
+					if task.Trashed() {
+						trash.Tasks = append(trash.Tasks, task)
+					} else {
+						c.Tasks = append(c.Tasks, task)
+					}
+				}
+			}
+			if c.Trashed() {
+				trash.Stacks = append(trash.Stacks, c)
+			} else if !deleted {
+				trash.Available = append(trash.Available, c)
+			}
+			walk(c.ID, nested, deleted || c.Trashed())
+		}
+	}
+	walk("", store.Get(), false)
+	slices.SortFunc(trash.Stacks, func(x, y Stack) int {
+		return y.Deleted.Compare(x.Deleted)
+	})
+	slices.SortFunc(trash.Tasks, func(x, y Task) int {
+		return y.Deleted.Compare(x.Deleted)
+	})
+	SortStacks(trash.Available)
+	return trash
+}
+
+// Orphaned indicates the task's stack is no longer available (e.g. also in the trash)
+func (t Trash) Orphaned(task Task) bool {
+	return FindByIndex(t.Available, task.StackID) == -1
+}
+
+// StackTitle will get the title of a stack (deleted or not) by ID
+func (t Trash) StackTitle(id string) string {
+	for _, set := range [][]Stack{t.Available, t.Stacks} {
+		if idx := FindByIndex(set, id); idx != -1 {
+			return set[idx].Title
+		}
+	}
+	return ""
+}
+
+// Empty indicates there is nothing in the trash
+func (t Trash) Empty() bool {
+	return len(t.Stacks) == 0 && len(t.Tasks) == 0
+}
+
+// PurgeTrash will permanently remove anything deleted before a given time
+func PurgeTrash(store backend.Store, before time.Time) {
+	trash := FetchTrash(store)
+	store.Batch(func() {
+		for _, t := range trash.Tasks {
+			if t.Deleted.Before(before) {
+				t.Purge(store)
+			}
+		}
+		for _, s := range trash.Stacks {
+			if s.Deleted.Before(before) {
+				s.Purge(store)
+			}
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package entities_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func TestFetchTrash(t *testing.T) {
+	m := &mockDB{}
+	now := time.Now()
+	m.data = make([]backend.Data, 3)
+	m.data[0].Node = entities.Stack{ID: "live", Title: "live"}
+	m.data[0].Children = make(backend.Map)
+	m.data[0].Children["a"] = backend.Data{Node: entities.Task{ID: "a", StackID: "live", Deleted: now.Add(-time.Hour)}}
+	m.data[0].Children["b"] = backend.Data{Node: entities.Task{ID: "b", StackID: "live"}}
+	m.data[1].Node = entities.Stack{ID: "dead", Deleted: now}
+	m.data[1].Children = make(backend.Map)
+	m.data[1].Children["c"] = backend.Data{Node: entities.Task{ID: "c", StackID: "dead"}}
+	m.data[1].Children["d"] = backend.Data{Node: entities.Task{ID: "d", StackID: "dead", Deleted: now}}
+	m.data[2].Node = 1
+	trash := entities.FetchTrash(m)
+	if trash.Empty() {
+		t.Error("trash should not be empty")
+	}
+	if len(trash.Stacks) != 1 || trash.Stacks[0].ID != "dead" || len(trash.Stacks[0].Tasks) != 1 {
+		t.Errorf("invalid stacks: %v", trash.Stacks)
+	}
+	if len(trash.Tasks) != 2 || trash.Tasks[0].ID != "d" || trash.Tasks[1].ID != "a" {
+		t.Errorf("invalid tasks: %v", trash.Tasks)
+	}
+	if !trash.Orphaned(trash.Tasks[0]) || trash.Orphaned(trash.Tasks[1]) {
+		t.Error("invalid orphan detection")
+	}
+	if len(trash.Available) != 1 || trash.StackTitle("live") != "live" || trash.StackTitle("x") != "" {
+		t.Errorf("invalid available stacks: %v", trash.Available)
+	}
+	if !entities.FetchTrash(&mockDB{}).Empty() {
+		t.Error("trash should be empty")
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	old := entities.Stack{ID: "old", Title: "old"}
+	old.Save(m)
+	old.Delete(m)
+	live := entities.Stack{ID: "live", Title: "live"}
+	live.Save(m)
+	task := entities.Task{ID: "task", Title: "task", StackID: "live"}
+	task.Save(m)
+	task.Delete(m)
+	recent := entities.Task{ID: "recent", Title: "recent", StackID: "live"}
+	recent.Save(m)
+	threshold := time.Now()
+	recent.Delete(m)
+	entities.PurgeTrash(m, threshold)
+	trash := entities.FetchTrash(m)
+	if len(trash.Stacks) != 0 || len(trash.Tasks) != 1 || trash.Tasks[0].ID != "recent" {
+		t.Errorf("invalid purge: %v", trash)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
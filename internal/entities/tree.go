@@ -0,0 +1,101 @@
+package entities
+
+import "time"
+
+// StackTree arranges stacks by their parent
+type StackTree struct {
+	stacks   map[string]Stack
+	children map[string][]Stack
+}
+
+// NewStackTree will arrange stacks as a tree, stacks with a parent that is not in the set are top-level
+func NewStackTree(stacks []Stack) StackTree {
+	t := StackTree{stacks: make(map[string]Stack), children: make(map[string][]Stack)}
+	for _, s := range stacks {
+		t.stacks[s.ID] = s
+	}
+	for _, s := range stacks {
+		parent := s.ParentID
+		if _, ok := t.stacks[parent]; !ok || parent == s.ID {
+			parent = ""
+		}
+		t.children[parent] = append(t.children[parent], s)
+	}
+	for _, c := range t.children {
+		SortStacks(c)
+	}
+	return t
+}
+
+// Children will get the (sorted) stacks directly under a stack, an empty id is the top-level
+func (t StackTree) Children(id string) []Stack {
+	return t.children[id]
+}
+
+func (t StackTree) nested(id string) []Stack {
+	if id == "" {
+		return nil
+	}
+	return t.children[id]
+}
+
+// HasChildren indicates if any stacks are nested under a stack
+func (t StackTree) HasChildren(id string) bool {
+	return len(t.nested(id)) > 0
+}
+
+// Ordered will get the stacks depth first, skipping the stacks nested under collapsed stacks
+func (t StackTree) Ordered(collapsed map[string]bool) []Stack {
+	var result []Stack
+	var walk func([]Stack)
+	walk = func(stacks []Stack) {
+		for _, s := range stacks {
+			result = append(result, s)
+			if !collapsed[s.ID] {
+				walk(t.nested(s.ID))
+			}
+		}
+	}
+	walk(t.children[""])
+	return result
+}
+
+// Depth will get how deeply a stack is nested (top-level stacks are 0)
+func (t StackTree) Depth(id string) int {
+	depth := 0
+	for parent := t.stacks[id].ParentID; parent != "" && parent != id; parent = t.stacks[parent].ParentID {
+		if _, ok := t.stacks[parent]; !ok {
+			break
+		}
+		depth++
+	}
+	return depth
+}
+
+// Descendants will get all stacks nested (at any depth) under a stack
+func (t StackTree) Descendants(id string) []Stack {
+	var result []Stack
+	for _, s := range t.nested(id) {
+		result = append(result, s)
+		result = append(result, t.Descendants(s.ID)...)
+	}
+	return result
+}
+
+// OpenTasks will get the count of unfinished tasks in a stack and all nested stacks
+func (t StackTree) OpenTasks(stack Stack) uint64 {
+	count := stack.OpenTasks()
+	for _, s := range t.Descendants(stack.ID) {
+		count += s.OpenTasks()
+	}
+	return count
+}
+
+// OverdueTasks will get the count of overdue tasks in a stack and all nested stacks
+func (t StackTree) OverdueTasks(stack Stack, now time.Time) uint64 {
+	count := stack.OverdueTasks(now)
+	for _, s := range t.Descendants(stack.ID) {
+		count += s.OverdueTasks(now)
+	}
+	return count
+}
@@ -0,0 +1,138 @@
+package entities_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+func newNestedStore() *backend.MemoryBased {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	for _, stack := range []entities.Stack{
+		{ID: "p", Title: "parent"},
+		{ID: "c", Title: "child", ParentID: "p"},
+		{ID: "g", Title: "grand", ParentID: "c"},
+		{ID: "o", Title: "other"},
+	} {
+		stack.Save(m)
+	}
+	for _, task := range []entities.Task{
+		{ID: "a", Title: "a", StackID: "p"},
+		{ID: "b", Title: "b", StackID: "g", Deadline: time.Now().Add(-time.Hour)},
+		{ID: "f", Title: "f", StackID: "g", Finished: time.Now()},
+	} {
+		task.Save(m)
+	}
+	return m
+}
+
+func TestListStacksNested(t *testing.T) {
+	m := newNestedStore()
+	stacks := entities.ListStacks(m)
+	if len(stacks) != 4 {
+		t.Errorf("invalid stacks: %v", stacks)
+	}
+	grand, ok := entities.FindStack(m, "g")
+	if !ok || grand.ParentID != "c" || len(grand.Tasks) != 2 {
+		t.Errorf("invalid nested stack: %v", grand)
+	}
+	task, ok := entities.FindTask(m, "b")
+	if !ok || task.StackID != "g" {
+		t.Errorf("invalid nested task: %v", task)
+	}
+	if m.Errored() {
+		t.Error("unexpected errors")
+	}
+}
+
+func TestStackTree(t *testing.T) {
+	m := newNestedStore()
+	tree := entities.NewStackTree(entities.ListStacks(m))
+	ids := func(stacks []entities.Stack) string {
+		var s string
+		for _, stack := range stacks {
+			s += stack.ID
+		}
+		return s
+	}
+	if s := ids(tree.Ordered(nil)); s != "opcg" {
+		t.Errorf("invalid order: %s", s)
+	}
+	if s := ids(tree.Ordered(map[string]bool{"p": true})); s != "op" {
+		t.Errorf("invalid collapsed order: %s", s)
+	}
+	if s := ids(tree.Children("")); s != "op" {
+		t.Errorf("invalid top-level: %s", s)
+	}
+	if s := ids(tree.Descendants("p")); s != "cg" {
+		t.Errorf("invalid descendants: %s", s)
+	}
+	if !tree.HasChildren("c") || tree.HasChildren("g") {
+		t.Error("invalid children")
+	}
+	if tree.Depth("g") != 2 || tree.Depth("o") != 0 {
+		t.Error("invalid depth")
+	}
+	parent, _ := entities.FindStack(m, "p")
+	if tree.OpenTasks(parent) != 2 || tree.OverdueTasks(parent, time.Now()) != 1 {
+		t.Error("invalid rollup")
+	}
+	// parents not in the set are top-level
+	if s := ids(entities.NewStackTree([]entities.Stack{{ID: "c", ParentID: "p"}}).Ordered(nil)); s != "c" {
+		t.Errorf("invalid orphan: %s", s)
+	}
+}
+
+func TestNestedTrash(t *testing.T) {
+	m := newNestedStore()
+	child, _ := entities.FindStack(m, "c")
+	child.Delete(m)
+	if s := entities.ListStacks(m); len(s) != 2 {
+		t.Errorf("nested stacks should be hidden: %v", s)
+	}
+	trash := entities.FetchTrash(m)
+	if len(trash.Stacks) != 1 || trash.Stacks[0].ID != "c" || len(trash.Available) != 2 {
+		t.Errorf("invalid trash: %v", trash)
+	}
+	trash.Stacks[0].Restore(m)
+	if s := entities.ListStacks(m); len(s) != 4 {
+		t.Errorf("nested stacks not restored: %v", s)
+	}
+	child.Delete(m)
+	entities.PurgeTrash(m, time.Now().Add(time.Hour))
+	if _, ok := entities.FindTask(m, "b"); ok {
+		t.Error("nested tasks should be purged")
+	}
+	if m.Errored() {
+		t.Error("unexpected errors")
+	}
+}
+
+func TestArchiveNestedStack(t *testing.T) {
+	live := newNestedStore()
+	var buf bytes.Buffer
+	archive := backend.NewMemoryBased("", false, &buf)
+	child, _ := entities.FindStack(live, "c")
+	entities.ArchiveStack(live, archive, child)
+	if s := entities.ListStacks(live); len(s) != 2 {
+		t.Errorf("stack not archived: %v", s)
+	}
+	if s := entities.ListStacks(archive); len(s) != 2 {
+		t.Errorf("invalid archive: %v", s)
+	}
+	entities.UnarchiveStack(live, archive, child)
+	grand, ok := entities.FindStack(live, "g")
+	if !ok || grand.ParentID != "c" || len(grand.Tasks) != 2 {
+		t.Errorf("nested stack not restored: %v", grand)
+	}
+	if restored, _ := entities.FindStack(live, "c"); restored.ParentID != "p" {
+		t.Errorf("stack not restored under parent: %v", restored)
+	}
+	if live.Errored() || archive.Errored() {
+		t.Error("unexpected errors")
+	}
+}
@@ -0,0 +1,134 @@
+// Package hooks runs user commands when stacks/tasks change
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+const (
+	envPrefix = "MAYHEM_"
+	// maxOutput limits how much hook output is logged on failure
+	maxOutput = 200
+)
+
+// Store wraps a store and runs hook commands (asynchronously) as entities change
+type Store struct {
+	backend.Store
+	commands map[string]string
+	timeout  time.Duration
+	running  sync.WaitGroup
+}
+
+// New will wrap a store with hooks (commands are keyed by event)
+func New(store backend.Store, commands map[string]string, timeout time.Duration) *Store {
+	return &Store{Store: store, commands: commands, timeout: timeout}
+}
+
+// Add will add an entity and fire any stack hooks
+func (s *Store) Add(id string, data any) {
+	s.stackChange(id, data, func() {
+		s.Store.Add(id, data)
+	})
+}
+
+// Nest will nest an entity and fire any stack hooks
+func (s *Store) Nest(parent, id string, data any) {
+	s.stackChange(id, data, func() {
+		s.Store.Nest(parent, id, data)
+	})
+}
+
+func (s *Store) stackChange(id string, data any, change func()) {
+	var prev any
+	for _, item := range backend.Flatten(s.Get()) {
+		if stack, ok := item.Node.(entities.Stack); ok && stack.ID == id {
+			prev = stack
+			break
+		}
+	}
+	change()
+	if stack, ok := data.(entities.Stack); ok {
+		s.fire(entities.StackEvent(prev, stack), stack.ID, stack.Title, "", stack)
+	}
+}
+
+// AddChild will add a child entity and fire any task hooks
+func (s *Store) AddChild(parent, id string, data any) {
+	var prev any
+	for _, item := range backend.Flatten(s.Get()) {
+		if child, ok := item.Children[id]; ok {
+			prev = child.Node
+			break
+		}
+	}
+	s.Store.AddChild(parent, id, data)
+	if task, ok := data.(entities.Task); ok {
+		s.fire(entities.TaskEvent(prev, task), task.ID, task.Title, task.StackID, task)
+	}
+}
+
+// Wait will wait for any running hooks to complete
+func (s *Store) Wait() {
+	s.running.Wait()
+}
+
+func (s *Store) fire(event, id, title, stackID string, entity any) {
+	command, ok := s.commands[event]
+	if !ok || strings.TrimSpace(command) == "" {
+		return
+	}
+	payload, err := json.Marshal(entity)
+	if err != nil {
+		s.Log("hook", fmt.Errorf("%s: %w", event, err))
+		return
+	}
+	env := []string{
+		envPrefix + "EVENT=" + event,
+		envPrefix + "ID=" + id,
+		envPrefix + "TITLE=" + title,
+		envPrefix + "TIME=" + time.Now().Format(time.RFC3339),
+	}
+	if stackID != "" {
+		env = append(env, envPrefix+"STACK_ID="+stackID)
+	}
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.Log("hook", s.run(event, command, payload, env))
+	}()
+}
+
+func (s *Store) run(event, command string, payload []byte, env []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %v", s.timeout)
+	}
+	output := strings.TrimSpace(string(out))
+	if runes := []rune(output); len(runes) > maxOutput {
+		output = string(runes[:maxOutput]) + "…"
+	}
+	if output != "" {
+		return fmt.Errorf("%s (%s): %w: %s", event, command, err, output)
+	}
+	return fmt.Errorf("%s (%s): %w", event, command, err)
+}
@@ -0,0 +1,83 @@
+package hooks_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/hooks"
+)
+
+func TestHooks(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	record := "cat > " + dir + "/$MAYHEM_EVENT.json; echo \"$MAYHEM_EVENT $MAYHEM_TITLE $MAYHEM_STACK_ID\" >> " + dir + "/events"
+	commands := make(map[string]string)
+	for _, event := range []string{entities.TaskAdd, entities.TaskUpdate, entities.TaskFinish, entities.TaskDelete, entities.StackAdd, entities.StackDelete} {
+		commands[event] = record
+	}
+	h := hooks.New(m, commands, 5*time.Second)
+	stack := entities.Stack{ID: "s", Title: "stack"}
+	stack.Save(h)
+	h.Wait()
+	task := entities.Task{ID: "t", Title: "task", StackID: "s"}
+	task = task.Save(h).(entities.Task)
+	h.Wait()
+	task.Notes = "notes"
+	task = task.Save(h).(entities.Task)
+	h.Wait()
+	task.Finished = time.Now()
+	task = task.Save(h).(entities.Task)
+	h.Wait()
+	task.Delete(h)
+	h.Wait()
+	stack.Title = "renamed"
+	stack.Save(h)
+	stack.Delete(h)
+	h.Wait()
+	b, err := os.ReadFile(filepath.Join(dir, "events"))
+	if err != nil {
+		t.Fatalf("no events: %v", err)
+	}
+	expect := "stack.add stack \ntask.add task s\ntask.update task s\ntask.finish task s\ntask.delete task s\nstack.delete renamed \n"
+	if string(b) != expect {
+		t.Errorf("invalid events: %s", string(b))
+	}
+	b, err = os.ReadFile(filepath.Join(dir, "task.finish.json"))
+	if err != nil {
+		t.Fatalf("no payload: %v", err)
+	}
+	var payload entities.Task
+	if err := json.Unmarshal(b, &payload); err != nil || payload.ID != "t" || payload.Finished.IsZero() {
+		t.Errorf("invalid payload: %v (%v)", payload, err)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
+
+func TestHookFailures(t *testing.T) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	h := hooks.New(m, map[string]string{entities.StackAdd: "echo broken; exit 3", entities.StackUpdate: "sleep 5"}, 100*time.Millisecond)
+	stack := entities.Stack{ID: "s", Title: "stack"}
+	stack.Save(h)
+	stack.Save(h)
+	h.Wait()
+	if !m.Errored() {
+		t.Error("hook failures should be logged")
+	}
+	log := buf.String()
+	for _, expect := range []string{"stack.add (echo broken; exit 3): exit status 3: broken", "stack.update (sleep 5): timed out after 100ms"} {
+		if !strings.Contains(log, expect) {
+			t.Errorf("missing '%s' in log: %s", expect, log)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package reminders finds tasks that are due soon and notifies about them
+package reminders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/enckse/mayhem/internal/durations"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+const (
+	// stale is how long after a deadline reminders are still considered
+	stale = 24 * time.Hour
+	// keep is how long fired reminders are remembered (after the deadline)
+	keep   = 7 * 24 * time.Hour
+	dateUI = "2006-01-02 15:04"
+)
+
+type (
+	// Reminder is a notification for a task deadline
+	Reminder struct {
+		Task  entities.Task
+		Stack string
+		Lead  time.Duration
+		// skipped are further out leads that were also due (and are superseded by this reminder)
+		skipped []time.Duration
+	}
+
+	// Fired tracks reminders that have already been sent
+	Fired map[string]FiredReminder
+
+	// FiredReminder is a reminder that was sent
+	FiredReminder struct {
+		Deadline time.Time
+		Fired    time.Time
+	}
+)
+
+// Due will find reminders to send (default leads apply to tasks without their own lead times)
+func Due(stacks []entities.Stack, leads []time.Duration, fired Fired, now time.Time) []Reminder {
+	var result []Reminder
+	for _, stack := range stacks {
+		for _, task := range stack.Tasks {
+			if task.Deadline.IsZero() || !task.Finished.IsZero() {
+				continue
+			}
+			if now.Sub(task.Deadline) > stale {
+				continue
+			}
+			taskLeads := task.Reminders
+			if len(taskLeads) == 0 {
+				taskLeads = leads
+			}
+			// only the closest lead is sent when several are due, the others are skipped
+			var due []time.Duration
+			for _, lead := range taskLeads {
+				if now.Before(task.Deadline.Add(-lead)) {
+					continue
+				}
+				if lead > 0 && !now.Before(task.Deadline) {
+					continue
+				}
+				if _, ok := fired[key(task, lead)]; ok {
+					continue
+				}
+				due = append(due, lead)
+			}
+			if len(due) == 0 {
+				continue
+			}
+			slices.Sort(due)
+			result = append(result, Reminder{Task: task, Stack: stack.Title, Lead: due[0], skipped: due[1:]})
+		}
+	}
+	slices.SortFunc(result, func(x, y Reminder) int {
+		return x.Task.Deadline.Compare(y.Task.Deadline)
+	})
+	return result
+}
+
+func key(task entities.Task, lead time.Duration) string {
+	return fmt.Sprintf("%s/%d/%d", task.ID, lead, task.Deadline.Unix())
+}
+
+// Message will get a human readable message for the reminder
+func (r Reminder) Message(now time.Time) string {
+	when := "is due"
+	if left := r.Task.Deadline.Sub(now); left > 0 {
+		when = fmt.Sprintf("is due in %s", durations.Format(left))
+	} else if left < -time.Minute {
+		when = "is overdue"
+	}
+	return fmt.Sprintf("%s (%s) %s (%s)", r.Task.Title, r.Stack, when, r.Task.Deadline.Format(dateUI))
+}
+
+// Mark will record that the reminder was sent
+func (f Fired) Mark(r Reminder, now time.Time) {
+	for _, lead := range append([]time.Duration{r.Lead}, r.skipped...) {
+		f[key(r.Task, lead)] = FiredReminder{Deadline: r.Task.Deadline, Fired: now}
+	}
+}
+
+// Prune will forget reminders for deadlines that are long past
+func (f Fired) Prune(now time.Time) {
+	for k, v := range f {
+		if now.Sub(v.Deadline) > keep {
+			delete(f, k)
+		}
+	}
+}
+
+// LoadFired will load fired reminders from a file (a missing file is empty)
+func LoadFired(file string) (Fired, error) {
+	fired := make(Fired)
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fired, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fired); err != nil {
+		return nil, err
+	}
+	return fired, nil
+}
+
+// Save will write the fired reminders to a file
+func (f Fired) Save(file string) error {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0o644)
+}
@@ -0,0 +1,131 @@
+package reminders_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/reminders"
+)
+
+type failing struct{}
+
+func (failing) Notify(reminders.Reminder, time.Time) error {
+	return errors.New("failed")
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	leads := []time.Duration{24 * time.Hour, time.Hour}
+	stacks := []entities.Stack{{Title: "Work", Tasks: []entities.Task{
+		{ID: "soon", Title: "soon", Deadline: now.Add(30 * time.Minute)},
+		{ID: "tomorrow", Title: "tomorrow", Deadline: now.Add(20 * time.Hour)},
+		{ID: "later", Title: "later", Deadline: now.Add(48 * time.Hour)},
+		{ID: "custom", Title: "custom", Deadline: now.Add(48 * time.Hour), Reminders: []time.Duration{72 * time.Hour}},
+		{ID: "done", Title: "done", Deadline: now.Add(time.Hour), Finished: now},
+		{ID: "none", Title: "none"},
+		{ID: "overdue", Title: "overdue", Deadline: now.Add(-time.Hour)},
+		{ID: "ontime", Title: "ontime", Deadline: now.Add(-time.Hour), Reminders: []time.Duration{0}},
+		{ID: "stale", Title: "stale", Deadline: now.Add(-48 * time.Hour), Reminders: []time.Duration{0}},
+	}}}
+	fired := make(reminders.Fired)
+	due := reminders.Due(stacks, leads, fired, now)
+	var got []string
+	for _, r := range due {
+		got = append(got, r.Task.ID+" "+r.Lead.String())
+	}
+	if strings.Join(got, ",") != "ontime 0s,soon 1h0m0s,tomorrow 24h0m0s,custom 72h0m0s" {
+		t.Errorf("invalid due: %v", got)
+	}
+	if msg := due[1].Message(now); msg != "soon (Work) is due in 30m (2026-01-10 12:30)" {
+		t.Errorf("invalid message: %s", msg)
+	}
+	if msg := due[0].Message(now); msg != "ontime (Work) is overdue (2026-01-10 11:00)" {
+		t.Errorf("invalid message: %s", msg)
+	}
+	if err := reminders.Send(due, failing{}, fired, now); err == nil || len(fired) != 0 {
+		t.Errorf("failures should not be marked: %v", fired)
+	}
+	notifier, err := reminders.NewNotifier(reminders.FileNotifier, "", filepath.Join(t.TempDir(), "reminders.txt"))
+	if err != nil {
+		t.Fatalf("invalid notifier: %v", err)
+	}
+	if err := reminders.Send(due, notifier, fired, now); err != nil {
+		t.Errorf("invalid send: %v", err)
+	}
+	if len(reminders.Due(stacks, leads, fired, now)) != 0 {
+		t.Error("reminders should only fire once")
+	}
+	// the 1d lead for 'soon' was superseded by the 1h reminder, 'tomorrow' is due again at 1h
+	if due := reminders.Due(stacks, leads, fired, now.Add(19*time.Hour+30*time.Minute)); len(due) != 1 || due[0].Task.ID != "tomorrow" || due[0].Lead != time.Hour {
+		t.Errorf("invalid later reminders: %v", due)
+	}
+	stacks[0].Tasks[0].Deadline = now.Add(45 * time.Minute)
+	if due := reminders.Due(stacks, leads, fired, now); len(due) != 1 || due[0].Task.ID != "soon" {
+		t.Errorf("changed deadline should remind again: %v", due)
+	}
+}
+
+func TestFired(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fired.json")
+	fired, err := reminders.LoadFired(file)
+	if err != nil || len(fired) != 0 {
+		t.Errorf("invalid missing file: %v (%v)", fired, err)
+	}
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	stacks := []entities.Stack{{Title: "Work", Tasks: []entities.Task{
+		{ID: "a", Title: "a", Deadline: now.Add(time.Minute)},
+		{ID: "b", Title: "b", Deadline: now.Add(-23 * time.Hour), Reminders: []time.Duration{0}},
+	}}}
+	for _, r := range reminders.Due(stacks, []time.Duration{time.Hour}, fired, now) {
+		fired.Mark(r, now)
+	}
+	if err := fired.Save(file); err != nil {
+		t.Errorf("invalid save: %v", err)
+	}
+	loaded, err := reminders.LoadFired(file)
+	if err != nil || len(loaded) != 2 {
+		t.Errorf("invalid load: %v (%v)", loaded, err)
+	}
+	loaded.Prune(now.Add(7 * 24 * time.Hour))
+	if len(loaded) != 1 {
+		t.Errorf("invalid prune: %v", loaded)
+	}
+	os.WriteFile(file, []byte("{"), 0o644)
+	if _, err := reminders.LoadFired(file); err == nil {
+		t.Error("invalid file should fail")
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	if _, err := reminders.NewNotifier("email", "", ""); err == nil {
+		t.Error("unknown notifier should fail")
+	}
+	for _, kind := range []string{reminders.CommandNotifier, reminders.FileNotifier} {
+		if _, err := reminders.NewNotifier(kind, "", ""); err == nil {
+			t.Errorf("%s notifier should require settings", kind)
+		}
+	}
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	r := reminders.Reminder{Task: entities.Task{Title: "a", Deadline: now.Add(time.Hour)}, Stack: "s", Lead: time.Hour}
+	notifier, err := reminders.NewNotifier(reminders.CommandNotifier, "echo \"$MAYHEM_LEAD $MAYHEM_MESSAGE\" > "+dir+"/out", "")
+	if err != nil {
+		t.Fatalf("invalid notifier: %v", err)
+	}
+	if err := notifier.Notify(r, now); err != nil {
+		t.Errorf("invalid notify: %v", err)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, "out"))
+	if string(b) != "1h a (s) is due in 1h (2026-01-10 13:00)\n" {
+		t.Errorf("invalid command output: %s", string(b))
+	}
+	notifier, _ = reminders.NewNotifier(reminders.CommandNotifier, "exit 1", "")
+	if err := notifier.Notify(r, now); err == nil {
+		t.Error("failing command should error")
+	}
+}
@@ -0,0 +1,122 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/enckse/mayhem/internal/durations"
+)
+
+const (
+	// StdoutNotifier writes reminders to stdout
+	StdoutNotifier = "stdout"
+	// CommandNotifier runs a command per reminder
+	CommandNotifier = "command"
+	// FileNotifier appends reminders to a file
+	FileNotifier = "file"
+
+	commandTimeout = 30 * time.Second
+)
+
+type (
+	// Notifier sends reminders
+	Notifier interface {
+		Notify(Reminder, time.Time) error
+	}
+
+	writerNotifier struct {
+		writer io.Writer
+	}
+
+	fileNotifier struct {
+		file string
+	}
+
+	commandNotifier struct {
+		command string
+	}
+)
+
+// NewNotifier will create a notifier by type (stdout when empty)
+func NewNotifier(kind, command, file string) (Notifier, error) {
+	switch kind {
+	case "", StdoutNotifier:
+		return writerNotifier{os.Stdout}, nil
+	case CommandNotifier:
+		if strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("%s notifier requires a command", kind)
+		}
+		return commandNotifier{command}, nil
+	case FileNotifier:
+		if strings.TrimSpace(file) == "" {
+			return nil, fmt.Errorf("%s notifier requires a file", kind)
+		}
+		return fileNotifier{file}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier: %s", kind)
+}
+
+func line(r Reminder, now time.Time) string {
+	return fmt.Sprintf("%s %s\n", now.Format(dateUI), r.Message(now))
+}
+
+func (n writerNotifier) Notify(r Reminder, now time.Time) error {
+	_, err := io.WriteString(n.writer, line(r, now))
+	return err
+}
+
+func (n fileNotifier) Notify(r Reminder, now time.Time) error {
+	f, err := os.OpenFile(n.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, line(r, now)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (n commandNotifier) Notify(r Reminder, now time.Time) error {
+	payload, err := json.Marshal(r.Task)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", n.command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"MAYHEM_MESSAGE="+r.Message(now),
+		"MAYHEM_TITLE="+r.Task.Title,
+		"MAYHEM_STACK="+r.Stack,
+		"MAYHEM_DEADLINE="+r.Task.Deadline.Format(time.RFC3339),
+		"MAYHEM_LEAD="+durations.Format(r.Lead),
+	)
+	cmd.WaitDelay = time.Second
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", n.command, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Send will notify for each reminder, marking those sent as fired (failures are returned once all are tried)
+func Send(items []Reminder, notifier Notifier, fired Fired, now time.Time) error {
+	var errs []error
+	for _, item := range items {
+		if err := notifier.Notify(item, now); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fired.Mark(item, now)
+	}
+	return errors.Join(errs...)
+}
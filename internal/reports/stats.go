@@ -0,0 +1,259 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/enckse/mayhem/internal/durations"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+const (
+	// DayPeriod buckets throughput per day
+	DayPeriod = "day"
+	// WeekPeriod buckets throughput per week (starting on Monday)
+	WeekPeriod = "week"
+
+	// dailyLimit is the longest window bucketed per day (longer windows are bucketed per week)
+	dailyLimit = 31 * 24 * time.Hour
+	barWidth   = 20
+	dayFormat  = "2006-01-02"
+)
+
+// sparks are the levels of a sparkline (lowest first)
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+type (
+	// StatsReport is throughput (tasks finished per day/week), open/finished/overdue tasks per stack and
+	// the average time from creation to finish within a window
+	StatsReport struct {
+		Since           time.Time
+		Until           time.Time
+		Period          string
+		Throughput      []PeriodCount
+		Open            int
+		Finished        int
+		Overdue         int
+		AverageToFinish time.Duration
+		Stacks          []StackStats
+	}
+
+	// PeriodCount is how many tasks were finished in a day/week
+	PeriodCount struct {
+		Start    time.Time `json:"start"`
+		Finished int       `json:"finished"`
+	}
+
+	// StackStats are the (currently) open and overdue tasks of a stack and the tasks finished in the window
+	StackStats struct {
+		Title    string `json:"title"`
+		Open     int    `json:"open"`
+		Finished int    `json:"finished"`
+		Overdue  int    `json:"overdue"`
+	}
+)
+
+// Stats will aggregate tasks finished within [since, until] (all time when since is zero) and the tasks open
+// (overdue when the deadline is before until), stacks without open or finished tasks are skipped
+func Stats(stacks []entities.Stack, since, until time.Time) StatsReport {
+	report := StatsReport{Since: since, Until: until}
+	first := since
+	var toFinish time.Duration
+	for _, stack := range stacks {
+		result := StackStats{Title: stack.Title}
+		for _, task := range stack.Tasks {
+			if task.Finished.IsZero() {
+				result.Open++
+				if !task.Deadline.IsZero() && task.Deadline.Before(until) {
+					result.Overdue++
+				}
+				continue
+			}
+			if task.Finished.Before(since) || task.Finished.After(until) {
+				continue
+			}
+			result.Finished++
+			toFinish += max(task.Finished.Sub(task.Created), 0)
+			if first.IsZero() || task.Finished.Before(first) {
+				first = task.Finished
+			}
+		}
+		if result.Open == 0 && result.Finished == 0 {
+			continue
+		}
+		report.Open += result.Open
+		report.Finished += result.Finished
+		report.Overdue += result.Overdue
+		report.Stacks = append(report.Stacks, result)
+	}
+	if report.Finished > 0 {
+		report.AverageToFinish = toFinish / time.Duration(report.Finished)
+	}
+	sort.SliceStable(report.Stacks, func(i, j int) bool {
+		return report.Stacks[i].Title < report.Stacks[j].Title
+	})
+	if first.IsZero() {
+		first = until
+	}
+	report.Period = DayPeriod
+	if until.Sub(first) > dailyLimit {
+		report.Period = WeekPeriod
+	}
+	for start := report.periodStart(first); !start.After(until); start = report.next(start) {
+		report.Throughput = append(report.Throughput, PeriodCount{Start: start})
+	}
+	for _, stack := range stacks {
+		for _, task := range stack.Tasks {
+			if task.Finished.IsZero() || task.Finished.Before(since) || task.Finished.After(until) {
+				continue
+			}
+			for idx := len(report.Throughput) - 1; idx >= 0; idx-- {
+				if !task.Finished.Before(report.Throughput[idx].Start) {
+					report.Throughput[idx].Finished++
+					break
+				}
+			}
+		}
+	}
+	return report
+}
+
+// periodStart is the start of the day/week of a time
+func (r StatsReport) periodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if r.Period == WeekPeriod {
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+func (r StatsReport) next(start time.Time) time.Time {
+	if r.Period == WeekPeriod {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Sparkline will display counts as a line of levels (relative to the largest count)
+func Sparkline(counts []int) string {
+	most := 0
+	for _, count := range counts {
+		most = max(most, count)
+	}
+	var b strings.Builder
+	for _, count := range counts {
+		level := 0
+		if most > 0 {
+			level = (count*(len(sparks)-1) + most - 1) / most
+		}
+		b.WriteRune(sparks[level])
+	}
+	return b.String()
+}
+
+// Bar will display a value as a bar of a width (relative to the largest value)
+func Bar(value, most, width int) string {
+	if most <= 0 || value <= 0 {
+		return ""
+	}
+	return strings.Repeat("█", max((value*width)/most, 1))
+}
+
+// bar is the finished (█) then open (░) tasks of the stack
+func (s StackStats) bar(most int) string {
+	total := len([]rune(Bar(s.Open+s.Finished, most, barWidth)))
+	finished := min(len([]rune(Bar(s.Finished, most, barWidth))), total)
+	return strings.Repeat("█", finished) + strings.Repeat("░", total-finished)
+}
+
+func (r StatsReport) window() string {
+	since := "the beginning"
+	if !r.Since.IsZero() {
+		since = r.Since.Format(reportTime)
+	}
+	return fmt.Sprintf("%s to %s", since, r.Until.Format(reportTime))
+}
+
+func (r StatsReport) averageToFinish() string {
+	if r.Finished == 0 {
+		return "-"
+	}
+	return durations.Format(r.AverageToFinish.Round(time.Minute))
+}
+
+// Write will write the report as text
+func (r StatsReport) Write(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tasks from %s\n", r.window())
+	fmt.Fprintf(&b, "finished %d · open %d · overdue %d · average to finish %s\n", r.Finished, r.Open, r.Overdue, r.averageToFinish())
+	if len(r.Throughput) > 0 {
+		var counts []int
+		most := 0
+		for _, period := range r.Throughput {
+			counts = append(counts, period.Finished)
+			most = max(most, period.Finished)
+		}
+		fmt.Fprintf(&b, "\nfinished per %s (most %d)\n%s\n", r.Period, most, Sparkline(counts))
+		fmt.Fprintf(&b, "%s to %s\n", r.Throughput[0].Start.Format(dayFormat), r.Throughput[len(r.Throughput)-1].Start.Format(dayFormat))
+	}
+	if len(r.Stacks) > 0 {
+		most := 0
+		for _, stack := range r.Stacks {
+			most = max(most, stack.Open+stack.Finished)
+		}
+		fmt.Fprintf(&b, "\n%-*s %8s %8s %8s\n", titleWidth, "stack", "open", "finished", "overdue")
+		for _, stack := range r.Stacks {
+			fmt.Fprintf(&b, "%-*s %8d %8d %8d  %s\n", titleWidth, truncate(stack.Title, titleWidth), stack.Open, stack.Finished, stack.Overdue, stack.bar(most))
+		}
+		fmt.Fprintf(&b, "%-*s  (█ finished, ░ open)\n", titleWidth+27, "")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown will write the report as markdown (e.g. for weekly reviews)
+func (r StatsReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Tasks from %s\n\n", r.window())
+	b.WriteString("| finished | open | overdue | average to finish |\n|---:|---:|---:|---:|\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %s |\n", r.Finished, r.Open, r.Overdue, r.averageToFinish())
+	if len(r.Throughput) > 0 {
+		fmt.Fprintf(&b, "\n## Finished per %s\n\n| %s | finished |\n|---|---:|\n", r.Period, r.Period)
+		for _, period := range r.Throughput {
+			fmt.Fprintf(&b, "| %s | %d |\n", period.Start.Format(dayFormat), period.Finished)
+		}
+	}
+	if len(r.Stacks) > 0 {
+		b.WriteString("\n## Stacks\n\n| stack | open | finished | overdue |\n|---|---:|---:|---:|\n")
+		for _, stack := range r.Stacks {
+			fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", strings.ReplaceAll(stack.Title, "|", `\|`), stack.Open, stack.Finished, stack.Overdue)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON will write the report as (indented) JSON, the average is in seconds
+func (r StatsReport) WriteJSON(w io.Writer) error {
+	var since *time.Time
+	if !r.Since.IsZero() {
+		since = &r.Since
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Since           *time.Time    `json:"since"`
+		Until           time.Time     `json:"until"`
+		Period          string        `json:"period"`
+		Finished        int           `json:"finished"`
+		Open            int           `json:"open"`
+		Overdue         int           `json:"overdue"`
+		AverageToFinish int64         `json:"average_to_finish_seconds"`
+		Throughput      []PeriodCount `json:"throughput"`
+		Stacks          []StackStats  `json:"stacks"`
+	}{since, r.Until, r.Period, r.Finished, r.Open, r.Overdue, int64(r.AverageToFinish.Seconds()), r.Throughput, r.Stacks})
+}
@@ -0,0 +1,99 @@
+package reports_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/reports"
+)
+
+func TestStats(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	task := func(title string, created, finished, deadline time.Duration) entities.Task {
+		task := entities.Task{Title: title}
+		task.Created = now.Add(-created)
+		if finished > 0 {
+			task.Finished = now.Add(-finished)
+		}
+		if deadline != 0 {
+			task.Deadline = now.Add(deadline)
+		}
+		return task
+	}
+	day := 24 * time.Hour
+	stacks := []entities.Stack{
+		{Title: "Work", Tasks: []entities.Task{
+			task("done", 3*day, day, 0),
+			task("done too", 2*day, day+time.Hour, 0),
+			task("late", 5*day, 0, -day),
+			task("soon", day, 0, day),
+			task("old", 90*day, 60*day, 0),
+		}},
+		{Title: "Home", Tasks: []entities.Task{task("today", 5*day, time.Hour, 0)}},
+		{Title: "Empty"},
+		{Title: "Archive", Tasks: []entities.Task{task("old", 90*day, 60*day, 0)}},
+	}
+	report := reports.Stats(stacks, now.Add(-7*day), now)
+	if report.Finished != 3 || report.Open != 2 || report.Overdue != 1 || len(report.Stacks) != 2 || report.Period != reports.DayPeriod {
+		t.Errorf("invalid report: %+v", report)
+	}
+	if report.AverageToFinish != (2*day+day-time.Hour+5*day-time.Hour)/3 {
+		t.Errorf("invalid average: %v", report.AverageToFinish)
+	}
+	if report.Stacks[0].Title != "Home" || report.Stacks[1].Open != 2 || report.Stacks[1].Finished != 2 {
+		t.Errorf("invalid stacks: %+v", report.Stacks)
+	}
+	if len(report.Throughput) != 8 || report.Throughput[6].Finished != 2 || report.Throughput[7].Finished != 1 {
+		t.Errorf("invalid throughput: %+v", report.Throughput)
+	}
+	all := reports.Stats(stacks, time.Time{}, now)
+	if all.Finished != 5 || len(all.Stacks) != 3 || all.Period != reports.WeekPeriod || all.Throughput[0].Start.Weekday() != time.Monday {
+		t.Errorf("invalid all time report: %+v", all)
+	}
+	var buf bytes.Buffer
+	if err := report.Write(&buf); err != nil {
+		t.Errorf("invalid write: %v", err)
+	}
+	text := buf.String()
+	for _, expect := range []string{"Tasks from 2026-01-03 12:00 to 2026-01-10 12:00", "finished 3 · open 2 · overdue 1", "finished per day (most 2)", "▁▁▁▁▁▁█▅", "██████████░░░░░░░░░░"} {
+		if !strings.Contains(text, expect) {
+			t.Errorf("missing '%s' in report: %s", expect, text)
+		}
+	}
+	buf.Reset()
+	if err := report.WriteMarkdown(&buf); err != nil {
+		t.Errorf("invalid write: %v", err)
+	}
+	for _, expect := range []string{"# Tasks from", "| 3 | 2 | 1 |", "| 2026-01-09 | 2 |", "| Work | 2 | 2 | 1 |"} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Errorf("missing '%s' in markdown: %s", expect, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := all.WriteJSON(&buf); err != nil {
+		t.Errorf("invalid write: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded["since"] != nil || decoded["finished"] != float64(5) || len(decoded["stacks"].([]any)) != 3 {
+		t.Errorf("invalid json: %v %s", err, buf.String())
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if line := reports.Sparkline([]int{0, 1, 4, 8}); line != "▁▂▅█" {
+		t.Errorf("invalid sparkline: %s", line)
+	}
+	if line := reports.Sparkline([]int{0, 0}); line != "▁▁" {
+		t.Errorf("invalid sparkline: %s", line)
+	}
+	if bar := reports.Bar(1, 100, 10); bar != "█" {
+		t.Errorf("invalid bar: %s", bar)
+	}
+	if bar := reports.Bar(0, 100, 10); bar != "" {
+		t.Errorf("invalid bar: %s", bar)
+	}
+}
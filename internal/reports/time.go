@@ -0,0 +1,103 @@
+// Package reports aggregates stack/task data into reports
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/enckse/mayhem/internal/durations"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+const (
+	reportTime = "2006-01-02 15:04"
+	titleWidth = 40
+)
+
+type (
+	// TimeReport is tracked time per stack/task within a window
+	TimeReport struct {
+		Since  time.Time
+		Until  time.Time
+		Total  time.Duration
+		Stacks []StackTime
+	}
+
+	// StackTime is the tracked time for a stack
+	StackTime struct {
+		Title string
+		Total time.Duration
+		Tasks []TaskTime
+	}
+
+	// TaskTime is the tracked time for a task
+	TaskTime struct {
+		Title    string
+		Tracked  time.Duration
+		Estimate time.Duration
+	}
+)
+
+// Time will aggregate tracked time within [since, until], stacks/tasks without tracked time are skipped
+func Time(stacks []entities.Stack, since, until time.Time) TimeReport {
+	report := TimeReport{Since: since, Until: until}
+	for _, stack := range stacks {
+		result := StackTime{Title: stack.Title}
+		for _, task := range stack.Tasks {
+			tracked := task.Tracked(since, until)
+			if tracked <= 0 {
+				continue
+			}
+			result.Total += tracked
+			result.Tasks = append(result.Tasks, TaskTime{Title: task.Title, Tracked: tracked, Estimate: task.Estimate})
+		}
+		if len(result.Tasks) == 0 {
+			continue
+		}
+		sort.SliceStable(result.Tasks, func(i, j int) bool {
+			if result.Tasks[i].Tracked != result.Tasks[j].Tracked {
+				return result.Tasks[i].Tracked > result.Tasks[j].Tracked
+			}
+			return result.Tasks[i].Title < result.Tasks[j].Title
+		})
+		report.Total += result.Total
+		report.Stacks = append(report.Stacks, result)
+	}
+	sort.SliceStable(report.Stacks, func(i, j int) bool {
+		return report.Stacks[i].Title < report.Stacks[j].Title
+	})
+	return report
+}
+
+// Write will write the report as text
+func (r TimeReport) Write(w io.Writer) error {
+	since := "the beginning"
+	if !r.Since.IsZero() {
+		since = r.Since.Format(reportTime)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Time tracked from %s to %s: %s\n", since, r.Until.Format(reportTime), durations.Format(r.Total))
+	for _, stack := range r.Stacks {
+		fmt.Fprintf(&b, "\n%-*s %s\n", titleWidth+2, truncate(stack.Title, titleWidth+2), durations.Format(stack.Total))
+		for _, task := range stack.Tasks {
+			fmt.Fprintf(&b, "  %-*s %s", titleWidth, truncate(task.Title, titleWidth), durations.Format(task.Tracked))
+			if task.Estimate > 0 {
+				fmt.Fprintf(&b, " (estimate %s)", durations.Format(task.Estimate))
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-1]) + "…"
+}
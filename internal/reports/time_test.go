@@ -0,0 +1,50 @@
+package reports_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/reports"
+)
+
+func TestTime(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	entry := func(start, length time.Duration) entities.TimeEntry {
+		return entities.TimeEntry{Start: now.Add(-start), Stop: now.Add(-start + length)}
+	}
+	stacks := []entities.Stack{
+		{Title: "Work", Tasks: []entities.Task{
+			{Title: "short", Time: []entities.TimeEntry{entry(2*time.Hour, 30*time.Minute)}},
+			{Title: "long", Estimate: 3 * time.Hour, Time: []entities.TimeEntry{entry(5*time.Hour, 2*time.Hour), {Start: now.Add(-time.Hour)}}},
+			{Title: "old", Time: []entities.TimeEntry{entry(30*24*time.Hour, time.Hour)}},
+		}},
+		{Title: "Home", Tasks: []entities.Task{{Title: "none"}}},
+		{Title: "Admin", Tasks: []entities.Task{{Title: "mail", Time: []entities.TimeEntry{entry(time.Hour, 15*time.Minute)}}}},
+	}
+	report := reports.Time(stacks, now.Add(-7*24*time.Hour), now)
+	if report.Total != 3*time.Hour+45*time.Minute || len(report.Stacks) != 2 {
+		t.Errorf("invalid report: %v", report)
+	}
+	if report.Stacks[0].Title != "Admin" || report.Stacks[1].Tasks[0].Title != "long" || len(report.Stacks[1].Tasks) != 2 {
+		t.Errorf("invalid ordering: %v", report.Stacks)
+	}
+	if all := reports.Time(stacks, time.Time{}, now); all.Total != 4*time.Hour+45*time.Minute {
+		t.Errorf("invalid all time report: %v", all.Total)
+	}
+	var buf bytes.Buffer
+	if err := report.Write(&buf); err != nil {
+		t.Errorf("invalid write: %v", err)
+	}
+	text := buf.String()
+	for _, expect := range []string{"Time tracked from 2026-01-03 12:00 to 2026-01-10 12:00: 3h45m", "  long", "3h (estimate 3h)", "Admin"} {
+		if !strings.Contains(text, expect) {
+			t.Errorf("missing '%s' in report: %s", expect, text)
+		}
+	}
+	if strings.Contains(text, "Home") || strings.Contains(text, "old") {
+		t.Errorf("unexpected entries in report: %s", text)
+	}
+}
@@ -0,0 +1,190 @@
+// Package revisions keeps the data directory as a git repository, committing as stacks/tasks change
+package revisions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultName  = "mayhem"
+	defaultEmail = "mayhem@localhost"
+)
+
+// ErrNoGit is returned when the git binary is not available
+var ErrNoGit = errors.New("git not found")
+
+type (
+	// Repo is a git repository tracking (only) the data files
+	Repo struct {
+		dir   string
+		git   string
+		files []string
+		// commits (e.g. of the data and archive stores) are serialized
+		lock sync.Mutex
+	}
+
+	// Revision is a commit in the history
+	Revision struct {
+		Hash    string
+		Time    time.Time
+		Message string
+	}
+)
+
+// Init will open the repository in a directory, creating it if needed
+func Init(dir string, files []string) (*Repo, error) {
+	r, err := newRepo(dir, files)
+	if err != nil {
+		return nil, err
+	}
+	if !r.exists() {
+		if _, err := r.run("init", "-q"); err != nil {
+			return nil, err
+		}
+	}
+	// commits must not fail because no identity is configured
+	if _, err := r.run("config", "user.email"); err != nil {
+		if _, err := r.run("config", "user.name", defaultName); err != nil {
+			return nil, err
+		}
+		if _, err := r.run("config", "user.email", defaultEmail); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Open will open an existing repository in a directory
+func Open(dir string, files []string) (*Repo, error) {
+	r, err := newRepo(dir, files)
+	if err != nil {
+		return nil, err
+	}
+	if !r.exists() {
+		return nil, fmt.Errorf("no history: %s is not a git repository", dir)
+	}
+	return r, nil
+}
+
+func newRepo(dir string, files []string) (*Repo, error) {
+	git, err := exec.LookPath("git")
+	if err != nil {
+		return nil, ErrNoGit
+	}
+	return &Repo{dir: dir, git: git, files: files}, nil
+}
+
+func (r *Repo) exists() bool {
+	_, err := os.Stat(filepath.Join(r.dir, ".git"))
+	return err == nil
+}
+
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := exec.Command(r.git, args...)
+	cmd.Dir = r.dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// tracked are the data files that exist (only existing files can be added)
+func (r *Repo) tracked() []string {
+	var files []string
+	for _, f := range r.files {
+		if _, err := os.Stat(filepath.Join(r.dir, f)); err == nil {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (r *Repo) hasCommits() bool {
+	_, err := r.run("rev-parse", "--verify", "-q", "HEAD")
+	return err == nil
+}
+
+// Commit will commit any changes to the data files (nothing is committed when unchanged)
+func (r *Repo) Commit(message string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	files := r.tracked()
+	if len(files) == 0 {
+		return nil
+	}
+	if _, err := r.run(append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+	if _, err := r.run("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	_, err := r.run("commit", "-q", "-m", message)
+	return err
+}
+
+// Log will get the most recent revisions (newest first, a limit <= 0 is all revisions)
+func (r *Repo) Log(limit int) ([]Revision, error) {
+	if !r.hasCommits() {
+		return nil, nil
+	}
+	args := []string{"log", "--format=%h%x09%aI%x09%s"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", limit))
+	}
+	out, err := r.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	var revisions []Revision
+	for line := range strings.SplitSeq(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		when, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, Revision{Hash: parts[0], Time: when, Message: parts[2]})
+	}
+	return revisions, nil
+}
+
+// Restore will bring the data files back to a revision (validate is called before committing, a failure reverts the files)
+func (r *Repo) Restore(rev string, validate func() error) error {
+	hash, err := r.run("rev-parse", "--verify", "-q", "--short", rev+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("unknown revision: %s", rev)
+	}
+	files, err := r.run(append([]string{"ls-tree", "--name-only", hash, "--"}, r.files...)...)
+	if err != nil {
+		return err
+	}
+	if files == "" {
+		return fmt.Errorf("no data files in revision: %s", rev)
+	}
+	if _, err := r.run(append([]string{"checkout", hash, "--"}, strings.Split(files, "\n")...)...); err != nil {
+		return err
+	}
+	if validate != nil {
+		if err := validate(); err != nil {
+			_, revert := r.run(append([]string{"checkout", "HEAD", "--"}, strings.Split(files, "\n")...)...)
+			return errors.Join(fmt.Errorf("invalid revision %s: %w", rev, err), revert)
+		}
+	}
+	return r.Commit("restore " + hash)
+}
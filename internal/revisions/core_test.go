@@ -0,0 +1,125 @@
+package revisions_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/revisions"
+)
+
+func newRepo(t *testing.T) (string, *revisions.Repo) {
+	dir := t.TempDir()
+	repo, err := revisions.Init(dir, []string{"todo.json"})
+	if errors.Is(err, revisions.ErrNoGit) {
+		t.Skip("git is not installed")
+	}
+	if err != nil {
+		t.Fatalf("invalid init: %v", err)
+	}
+	return dir, repo
+}
+
+func messages(t *testing.T, repo *revisions.Repo) string {
+	revs, err := repo.Log(0)
+	if err != nil {
+		t.Fatalf("invalid log: %v", err)
+	}
+	var result []string
+	for _, rev := range revs {
+		result = append(result, rev.Message)
+	}
+	return strings.Join(result, "\n")
+}
+
+func TestStore(t *testing.T) {
+	dir, repo := newRepo(t)
+	if revs, err := repo.Log(10); err != nil || len(revs) != 0 {
+		t.Errorf("invalid empty log: %v (%v)", revs, err)
+	}
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased(filepath.Join(dir, "todo.json"), false, &buf)
+	s := revisions.New(m, repo, 0)
+	stack := entities.Stack{ID: "s", Title: "Home"}
+	stack.Save(s)
+	task := entities.Task{ID: "t", Title: "Pay rent", StackID: "s"}
+	task = task.Save(s).(entities.Task)
+	task.Finished = time.Now()
+	task.Save(s)
+	s.Remove("s")
+	os.WriteFile(filepath.Join(dir, "other.txt"), []byte{}, 0o644)
+	if msg := messages(t, repo); msg != "remove stack: Home\nfinish task: Pay rent\nadd task: Pay rent\nadd stack: Home" {
+		t.Errorf("invalid history: %s", msg)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+	if err := repo.Commit("nothing"); err != nil || strings.Contains(messages(t, repo), "nothing") {
+		t.Errorf("unchanged files should not be committed: %v", err)
+	}
+}
+
+func TestStoreDebounce(t *testing.T) {
+	dir, repo := newRepo(t)
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased(filepath.Join(dir, "todo.json"), false, &buf)
+	s := revisions.New(m, repo, time.Hour)
+	for _, title := range []string{"a", "b"} {
+		stack := entities.Stack{ID: title, Title: title}
+		stack.Save(s)
+	}
+	if msg := messages(t, repo); msg != "" {
+		t.Errorf("committed before debounce: %s", msg)
+	}
+	s.Close()
+	if msg := messages(t, repo); msg != "add stack: a (and 1 more)" {
+		t.Errorf("invalid history: %s", msg)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
+
+func TestRestore(t *testing.T) {
+	dir, repo := newRepo(t)
+	var buf bytes.Buffer
+	file := filepath.Join(dir, "todo.json")
+	s := revisions.New(backend.NewMemoryBased(file, false, &buf), repo, 0)
+	stack := entities.Stack{ID: "s", Title: "first"}
+	stack.Save(s)
+	revs, _ := repo.Log(1)
+	first, _ := os.ReadFile(file)
+	stack.Title = "second"
+	stack.Save(s)
+	if err := repo.Restore("unknown", nil); err == nil {
+		t.Error("invalid revision restored")
+	}
+	second, _ := os.ReadFile(file)
+	if err := repo.Restore(revs[0].Hash, func() error { return errors.New("bad") }); err == nil {
+		t.Error("invalid restore should fail")
+	}
+	if b, _ := os.ReadFile(file); !bytes.Equal(b, second) {
+		t.Errorf("failed restore not reverted: %s", b)
+	}
+	if err := repo.Restore(revs[0].Hash, func() error { return nil }); err != nil {
+		t.Errorf("invalid restore: %v", err)
+	}
+	if b, _ := os.ReadFile(file); !bytes.Equal(b, first) {
+		t.Errorf("not restored: %s", b)
+	}
+	if msg := messages(t, repo); !strings.HasPrefix(msg, "restore "+revs[0].Hash) {
+		t.Errorf("invalid history: %s", msg)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := revisions.Open(t.TempDir(), nil); err == nil {
+		t.Error("not a repository")
+	}
+}
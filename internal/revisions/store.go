@@ -0,0 +1,157 @@
+package revisions
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+// Store wraps a store and commits the data files as entities change
+type Store struct {
+	backend.Store
+	repo     *Repo
+	debounce time.Duration
+	batching int
+	// pending changes (and the debounce timer) are shared with the timer goroutine
+	lock    sync.Mutex
+	pending []string
+	timer   *time.Timer
+}
+
+// New will wrap a store with commits (changes within the debounce window are committed together)
+func New(store backend.Store, repo *Repo, debounce time.Duration) *Store {
+	return &Store{Store: store, repo: repo, debounce: debounce}
+}
+
+// Add will add an entity and commit
+func (s *Store) Add(id string, data any) {
+	prev := s.find(id)
+	s.Store.Add(id, data)
+	s.changed(describe(prev, data))
+}
+
+// Nest will nest an entity and commit
+func (s *Store) Nest(parent, id string, data any) {
+	prev := s.find(id)
+	s.Store.Nest(parent, id, data)
+	s.changed(describe(prev, data))
+}
+
+// AddChild will add a child entity and commit
+func (s *Store) AddChild(parent, id string, data any) {
+	prev := s.find(id)
+	s.Store.AddChild(parent, id, data)
+	s.changed(describe(prev, data))
+}
+
+// Remove will remove an entity and commit
+func (s *Store) Remove(id string) {
+	prev := s.find(id)
+	s.Store.Remove(id)
+	s.changed(removal(prev))
+}
+
+// RemoveChild will remove a child entity and commit
+func (s *Store) RemoveChild(parent, id string) {
+	prev := s.find(id)
+	s.Store.RemoveChild(parent, id)
+	s.changed(removal(prev))
+}
+
+// Batch will run changes as a batch (committed together)
+func (s *Store) Batch(changes func()) {
+	s.batching++
+	defer func() {
+		s.batching--
+		s.changed("")
+	}()
+	s.Store.Batch(changes)
+}
+
+// Close will commit any pending changes
+func (s *Store) Close() {
+	s.lock.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.lock.Unlock()
+	s.flush()
+}
+
+func (s *Store) find(id string) any {
+	for _, item := range backend.Flatten(s.Get()) {
+		if stack, ok := item.Node.(entities.Stack); ok && stack.ID == id {
+			return stack
+		}
+		if child, ok := item.Children[id]; ok {
+			return child.Node
+		}
+	}
+	return nil
+}
+
+func (s *Store) changed(message string) {
+	s.lock.Lock()
+	if message != "" {
+		s.pending = append(s.pending, message)
+	}
+	if s.batching > 0 || len(s.pending) == 0 {
+		s.lock.Unlock()
+		return
+	}
+	if s.debounce > 0 {
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.debounce, s.flush)
+		} else {
+			s.timer.Reset(s.debounce)
+		}
+		s.lock.Unlock()
+		return
+	}
+	s.lock.Unlock()
+	s.flush()
+}
+
+func (s *Store) flush() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.pending) == 0 {
+		return
+	}
+	message := s.pending[0]
+	if len(s.pending) > 1 {
+		message = fmt.Sprintf("%s (and %d more)\n\n%s", message, len(s.pending)-1, strings.Join(s.pending, "\n"))
+	}
+	s.pending = nil
+	s.Log("git", s.repo.Commit(message))
+}
+
+func describe(prev, data any) string {
+	switch entity := data.(type) {
+	case entities.Stack:
+		return message(entities.StackEvent(prev, entity), entity.Title)
+	case entities.Task:
+		return message(entities.TaskEvent(prev, entity), entity.Title)
+	}
+	return "update data"
+}
+
+func removal(prev any) string {
+	switch entity := prev.(type) {
+	case entities.Stack:
+		return "remove stack: " + entity.Title
+	case entities.Task:
+		return "remove task: " + entity.Title
+	}
+	return "remove data"
+}
+
+// message will describe an event, e.g. "task.finish" is "finish task: <title>"
+func message(event, title string) string {
+	kind, action, _ := strings.Cut(event, ".")
+	return fmt.Sprintf("%s %s: %s", action, kind, title)
+}
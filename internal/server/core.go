@@ -0,0 +1,394 @@
+// Package server provides a JSON (REST) API over stacks/tasks
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/google/uuid"
+)
+
+const bearer = "Bearer "
+
+type (
+	// Server handles API requests against a store
+	Server struct {
+		store backend.Store
+		token string
+		mux   *http.ServeMux
+		// the store is not safe for concurrent use, all requests are serialized
+		lock sync.Mutex
+	}
+
+	// StackView is a stack with its tasks
+	StackView struct {
+		entities.Stack
+		Tasks []entities.Task
+	}
+
+	// StackUpdate is the set of fields allowed when creating/updating a stack
+	StackUpdate struct {
+		Title *string
+	}
+
+	// TaskUpdate is the set of fields allowed when creating/updating a task
+	TaskUpdate struct {
+		Title     *string
+		Notes     *string
+		Deadline  *time.Time
+		Priority  *uint64
+		Estimate  *time.Duration
+		Reminders *[]time.Duration
+	}
+
+	// MoveRequest moves a task to a different stack
+	MoveRequest struct {
+		StackID string
+	}
+
+	// ErrorResponse is the body for any failed request
+	ErrorResponse struct {
+		Error string
+	}
+
+	statusError struct {
+		status int
+		err    error
+	}
+
+	// listQuery sorts/filters a list: sort (created/updated, tasks take any sort strategy) and the
+	// created_after/updated_after (RFC3339) times
+	listQuery struct {
+		sort         string
+		createdAfter time.Time
+		updatedAfter time.Time
+	}
+
+	handler func(*http.Request) (int, any, error)
+)
+
+// New will create a new API server (an empty token disables authorization)
+func New(store backend.Store, token string) *Server {
+	s := &Server{store: store, token: token, mux: http.NewServeMux()}
+	s.handle("GET /stacks", s.listStacks)
+	s.handle("POST /stacks", s.createStack)
+	s.handle("GET /stacks/{id}", s.getStack)
+	s.handle("PATCH /stacks/{id}", s.updateStack)
+	s.handle("DELETE /stacks/{id}", s.deleteStack)
+	s.handle("GET /stacks/{id}/tasks", s.listTasks)
+	s.handle("POST /stacks/{id}/tasks", s.createTask)
+	s.handle("GET /tasks/{id}", s.getTask)
+	s.handle("PATCH /tasks/{id}", s.updateTask)
+	s.handle("DELETE /tasks/{id}", s.deleteTask)
+	s.handle("POST /tasks/{id}/move", s.moveTask)
+	s.handle("POST /tasks/{id}/toggle", s.toggleTask)
+	return s
+}
+
+// ServeHTTP will handle an API request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, bearer) || subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, bearer)), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			write(w, http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handle(pattern string, h handler) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		status, body, err := h(r)
+		s.lock.Unlock()
+		if err != nil {
+			status = http.StatusInternalServerError
+			var serr statusError
+			if errors.As(err, &serr) {
+				status = serr.status
+			} else {
+				s.store.Log("server", err)
+			}
+			write(w, status, ErrorResponse{Error: err.Error()})
+			return
+		}
+		write(w, status, body)
+	})
+}
+
+func write(w http.ResponseWriter, status int, body any) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+func notFound(what string) error {
+	return statusError{http.StatusNotFound, errors.New(what + " not found")}
+}
+
+func badRequest(err error) error {
+	return statusError{http.StatusBadRequest, err}
+}
+
+func decode(r *http.Request, obj any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return badRequest(err)
+	}
+	return nil
+}
+
+func newListQuery(r *http.Request) (listQuery, error) {
+	query := listQuery{sort: r.URL.Query().Get("sort")}
+	for name, target := range map[string]*time.Time{"created_after": &query.createdAfter, "updated_after": &query.updatedAfter} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, badRequest(fmt.Errorf("invalid %s: %w", name, err))
+		}
+		*target = parsed
+	}
+	return query, nil
+}
+
+func newStackView(stack entities.Stack) StackView {
+	entities.SortTasksBy(stack.Tasks, stack.Sort)
+	tasks := stack.Tasks
+	if tasks == nil {
+		tasks = []entities.Task{}
+	}
+	return StackView{Stack: stack, Tasks: tasks}
+}
+
+func (s *Server) stack(r *http.Request) (entities.Stack, error) {
+	stack, ok := entities.FindStack(s.store, r.PathValue("id"))
+	if !ok {
+		return stack, notFound("stack")
+	}
+	return stack, nil
+}
+
+func (s *Server) task(r *http.Request) (entities.Task, error) {
+	task, ok := entities.FindTask(s.store, r.PathValue("id"))
+	if !ok {
+		return task, notFound("task")
+	}
+	return task, nil
+}
+
+func (s *Server) listStacks(r *http.Request) (int, any, error) {
+	query, err := newListQuery(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	stacks := entities.FilterByTimestamps(entities.ListStacks(s.store), query.createdAfter, query.updatedAfter)
+	switch query.sort {
+	case "":
+		entities.SortStacks(stacks)
+	case string(entities.SortCreated):
+		entities.SortByCreated(stacks)
+	case string(entities.SortUpdated):
+		entities.SortByUpdated(stacks)
+	default:
+		return 0, nil, badRequest(fmt.Errorf("invalid sort: %s", query.sort))
+	}
+	views := []StackView{}
+	for _, stack := range stacks {
+		views = append(views, newStackView(stack))
+	}
+	return http.StatusOK, views, nil
+}
+
+func (s *Server) getStack(r *http.Request) (int, any, error) {
+	stack, err := s.stack(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, newStackView(stack), nil
+}
+
+func (s *Server) saveStack(stack entities.Stack, r *http.Request) (entities.Stack, error) {
+	var update StackUpdate
+	if err := decode(r, &update); err != nil {
+		return stack, err
+	}
+	if update.Title != nil {
+		stack.Title = *update.Title
+	}
+	if err := stack.Validate(); err != nil {
+		return stack, badRequest(err)
+	}
+	return stack.Save(s.store).(entities.Stack), nil
+}
+
+func (s *Server) createStack(r *http.Request) (int, any, error) {
+	stack := entities.Stack{ID: uuid.NewString()}
+	stack, err := s.saveStack(stack, r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, newStackView(stack), nil
+}
+
+func (s *Server) updateStack(r *http.Request) (int, any, error) {
+	stack, err := s.stack(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if stack, err = s.saveStack(stack, r); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, newStackView(stack), nil
+}
+
+func (s *Server) deleteStack(r *http.Request) (int, any, error) {
+	stack, err := s.stack(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	stack.Delete(s.store)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) listTasks(r *http.Request) (int, any, error) {
+	stack, err := s.stack(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	query, err := newListQuery(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if query.sort != "" {
+		strategy := entities.SortStrategy(query.sort)
+		if strategy == entities.SortDefault || !slices.Contains(entities.SortStrategies, strategy) {
+			return 0, nil, badRequest(fmt.Errorf("invalid sort: %s", query.sort))
+		}
+		stack.Sort = strategy
+	}
+	tasks := newStackView(stack).Tasks
+	return http.StatusOK, entities.FilterByTimestamps(tasks, query.createdAfter, query.updatedAfter), nil
+}
+
+func (s *Server) saveTask(task entities.Task, r *http.Request) (entities.Task, error) {
+	var update TaskUpdate
+	if err := decode(r, &update); err != nil {
+		return task, err
+	}
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Notes != nil {
+		task.Notes = *update.Notes
+	}
+	if update.Deadline != nil {
+		task.Deadline = *update.Deadline
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.Estimate != nil {
+		if *update.Estimate < 0 {
+			return task, badRequest(errors.New("invalid estimate"))
+		}
+		task.Estimate = *update.Estimate
+	}
+	if update.Reminders != nil {
+		if slices.ContainsFunc(*update.Reminders, func(d time.Duration) bool { return d < 0 }) {
+			return task, badRequest(errors.New("invalid reminders"))
+		}
+		task.Reminders = *update.Reminders
+	}
+	if err := task.Validate(); err != nil {
+		return task, badRequest(err)
+	}
+	return task.Save(s.store).(entities.Task), nil
+}
+
+func (s *Server) createTask(r *http.Request) (int, any, error) {
+	stack, err := s.stack(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	task := entities.NewTask()
+	task.StackID = stack.ID
+	if task, err = s.saveTask(task, r); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, task, nil
+}
+
+func (s *Server) getTask(r *http.Request) (int, any, error) {
+	task, err := s.task(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, task, nil
+}
+
+func (s *Server) updateTask(r *http.Request) (int, any, error) {
+	task, err := s.task(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if task, err = s.saveTask(task, r); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, task, nil
+}
+
+func (s *Server) deleteTask(r *http.Request) (int, any, error) {
+	task, err := s.task(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	task.Delete(s.store)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) moveTask(r *http.Request) (int, any, error) {
+	task, err := s.task(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req MoveRequest
+	if err := decode(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if _, ok := entities.FindStack(s.store, req.StackID); !ok {
+		return 0, nil, badRequest(errors.New("target stack not found"))
+	}
+	task.StackID = req.StackID
+	return http.StatusOK, task.Save(s.store), nil
+}
+
+func (s *Server) toggleTask(r *http.Request) (int, any, error) {
+	task, err := s.task(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, task.ToggleFinished(s.store, time.Now()), nil
+}
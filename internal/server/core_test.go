@@ -0,0 +1,173 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/server"
+)
+
+type client struct {
+	t     *testing.T
+	url   string
+	token string
+}
+
+func (c client) do(method, path, body string, status int, result any) {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		c.t.Fatalf("invalid request: %v", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		b, _ := io.ReadAll(resp.Body)
+		c.t.Errorf("%s %s: invalid status %d != %d (%s)", method, path, resp.StatusCode, status, string(b))
+		return
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			c.t.Errorf("invalid response: %v", err)
+		}
+	}
+}
+
+func newServer(t *testing.T, token string) (client, *backend.MemoryBased, *bytes.Buffer) {
+	var buf bytes.Buffer
+	m := backend.NewMemoryBased("", false, &buf)
+	ts := httptest.NewServer(server.New(m, token))
+	t.Cleanup(ts.Close)
+	return client{t: t, url: ts.URL, token: token}, m, &buf
+}
+
+func TestStacks(t *testing.T) {
+	c, m, buf := newServer(t, "")
+	var stack server.StackView
+	c.do("POST", "/stacks", `{"Title": "Work"}`, http.StatusCreated, &stack)
+	if stack.ID == "" || stack.Title != "Work" || stack.Created.IsZero() || len(stack.Tasks) != 0 {
+		t.Errorf("invalid stack: %v", stack)
+	}
+	c.do("POST", "/stacks", `{"Title": ""}`, http.StatusBadRequest, nil)
+	c.do("POST", "/stacks", `{"Other": "x"}`, http.StatusBadRequest, nil)
+	c.do("POST", "/stacks", `{"Title": "Home"}`, http.StatusCreated, nil)
+	var stacks []server.StackView
+	c.do("GET", "/stacks", "", http.StatusOK, &stacks)
+	if len(stacks) != 2 || stacks[0].Title != "Home" {
+		t.Errorf("invalid stacks: %v", stacks)
+	}
+	c.do("GET", "/stacks?sort=created", "", http.StatusOK, &stacks)
+	if len(stacks) != 2 || stacks[0].Title != "Work" {
+		t.Errorf("invalid created stacks: %v", stacks)
+	}
+	c.do("GET", "/stacks?sort=updated", "", http.StatusOK, &stacks)
+	if len(stacks) != 2 || stacks[0].Title != "Home" {
+		t.Errorf("invalid updated stacks: %v", stacks)
+	}
+	c.do("GET", "/stacks?created_after="+stack.Created.Add(time.Hour).Format(time.RFC3339), "", http.StatusOK, &stacks)
+	if len(stacks) != 0 {
+		t.Errorf("invalid filtered stacks: %v", stacks)
+	}
+	c.do("GET", "/stacks?sort=priority", "", http.StatusBadRequest, nil)
+	c.do("GET", "/stacks?updated_after=yesterday", "", http.StatusBadRequest, nil)
+	c.do("PATCH", "/stacks/"+stack.ID, `{"Title": "Job"}`, http.StatusOK, &stack)
+	if stack.Title != "Job" {
+		t.Errorf("invalid update: %v", stack)
+	}
+	c.do("GET", "/stacks/"+stack.ID, "", http.StatusOK, &stack)
+	if stack.Title != "Job" {
+		t.Errorf("invalid get: %v", stack)
+	}
+	c.do("GET", "/stacks/missing", "", http.StatusNotFound, nil)
+	c.do("DELETE", "/stacks/"+stack.ID, "", http.StatusNoContent, nil)
+	c.do("GET", "/stacks/"+stack.ID, "", http.StatusNotFound, nil)
+	if trash := entities.FetchTrash(m); len(trash.Stacks) != 1 {
+		t.Errorf("stack not in trash: %v", trash)
+	}
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
+
+func TestTasks(t *testing.T) {
+	c, m, buf := newServer(t, "")
+	var work, home server.StackView
+	c.do("POST", "/stacks", `{"Title": "Work"}`, http.StatusCreated, &work)
+	c.do("POST", "/stacks", `{"Title": "Home"}`, http.StatusCreated, &home)
+	var task entities.Task
+	c.do("POST", "/stacks/"+work.ID+"/tasks", `{"Title": "Pay rent", "Priority": 2, "Deadline": "2026-01-02T03:04:00Z"}`, http.StatusCreated, &task)
+	if task.ID == "" || task.StackID != work.ID || task.Priority != 2 || task.Deadline.IsZero() {
+		t.Errorf("invalid task: %v", task)
+	}
+	c.do("POST", "/stacks/"+work.ID+"/tasks", `{"Title": "x", "Priority": 9}`, http.StatusBadRequest, nil)
+	c.do("POST", "/stacks/"+work.ID+"/tasks", `{"Notes": "no title"}`, http.StatusBadRequest, nil)
+	c.do("POST", "/stacks/missing/tasks", `{"Title": "x"}`, http.StatusNotFound, nil)
+	var tasks []entities.Task
+	c.do("GET", "/stacks/"+work.ID+"/tasks", "", http.StatusOK, &tasks)
+	if len(tasks) != 1 {
+		t.Errorf("invalid tasks: %v", tasks)
+	}
+	c.do("GET", "/stacks/"+work.ID+"/tasks?sort=updated&updated_after="+task.Updated.Add(time.Hour).Format(time.RFC3339), "", http.StatusOK, &tasks)
+	if len(tasks) != 0 {
+		t.Errorf("invalid filtered tasks: %v", tasks)
+	}
+	c.do("GET", "/stacks/"+work.ID+"/tasks?sort=default", "", http.StatusBadRequest, nil)
+	c.do("GET", "/stacks/"+work.ID+"/tasks?sort=other", "", http.StatusBadRequest, nil)
+	c.do("PATCH", "/tasks/"+task.ID, `{"Notes": "monthly"}`, http.StatusOK, &task)
+	if task.Notes != "monthly" || task.Title != "Pay rent" || len(task.History) != 1 {
+		t.Errorf("invalid update: %v", task)
+	}
+	c.do("GET", "/stacks/"+work.ID, "", http.StatusOK, &work)
+	updated := work.Updated
+	c.do("POST", "/tasks/"+task.ID+"/toggle", "", http.StatusOK, &task)
+	if task.Finished.IsZero() {
+		t.Errorf("task not finished: %v", task)
+	}
+	c.do("GET", "/stacks/"+work.ID, "", http.StatusOK, &work)
+	if !work.Updated.After(updated) {
+		t.Errorf("stack not saved on toggle: %v", work)
+	}
+	c.do("POST", "/tasks/"+task.ID+"/toggle", "", http.StatusOK, &task)
+	if !task.Finished.IsZero() {
+		t.Errorf("task still finished: %v", task)
+	}
+	c.do("POST", "/tasks/"+task.ID+"/move", `{"StackID": "missing"}`, http.StatusBadRequest, nil)
+	c.do("POST", "/tasks/"+task.ID+"/move", `{"StackID": "`+home.ID+`"}`, http.StatusOK, &task)
+	c.do("GET", "/stacks/"+home.ID, "", http.StatusOK, &home)
+	if task.StackID != home.ID || len(home.Tasks) != 1 {
+		t.Errorf("task not moved: %v", home)
+	}
+	c.do("GET", "/tasks/"+task.ID, "", http.StatusOK, &task)
+	c.do("DELETE", "/tasks/"+task.ID, "", http.StatusNoContent, nil)
+	c.do("GET", "/tasks/"+task.ID, "", http.StatusNotFound, nil)
+	c.do("PATCH", "/tasks/"+task.ID, `{"Notes": "x"}`, http.StatusNotFound, nil)
+	if m.Errored() {
+		t.Errorf("unexpected errors: %s", buf.String())
+	}
+}
+
+func TestToken(t *testing.T) {
+	c, _, _ := newServer(t, "secret")
+	c.do("GET", "/stacks", "", http.StatusOK, nil)
+	c.token = "wrong"
+	c.do("GET", "/stacks", "", http.StatusUnauthorized, nil)
+	c.token = ""
+	c.do("GET", "/stacks", "", http.StatusUnauthorized, nil)
+}
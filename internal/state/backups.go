@@ -1,16 +1,38 @@
 package state
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/durations"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+const (
+	backupFormat = "20060102T150405"
+	backupSuffix = "." + databaseName
+	gzipSuffix   = ".gz"
 )
 
-// Backup will perform a backup based on the configuration
+// BackupFile is a backup of the database
+type BackupFile struct {
+	Name       string
+	Path       string
+	Size       int64
+	Time       time.Time
+	Compressed bool
+}
+
+// Backup will perform a backup based on the configuration (and then apply retention)
 func (c Config) Backup(timestamp time.Time) error {
 	db := c.Database()
 	if !PathExists(db) {
@@ -19,40 +41,175 @@ func (c Config) Backup(timestamp time.Time) error {
 	if err := os.MkdirAll(c.Backups.Directory, os.ModePerm); err != nil {
 		return err
 	}
-	if c.Backups.Duration != "" {
-		dur, err := time.ParseDuration(c.Backups.Duration)
-		if err != nil {
+	target := filepath.Join(c.Backups.Directory, timestamp.Format(c.timestampFormat())+backupSuffix)
+	if c.Backups.Compress {
+		target += gzipSuffix
+	}
+	if !PathExists(target) {
+		if err := copyFile(db, target, c.Backups.Compress); err != nil {
 			return err
 		}
-		threshold := time.Now().Add(-dur)
-		err = filepath.Walk(c.Backups.Directory, func(p string, info fs.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if strings.HasSuffix(p, "."+databaseName) {
-				if info.ModTime().Before(threshold) {
-					if err := os.Remove(p); err != nil {
-						return err
-					}
-				}
-			}
-			return nil
-		})
-		if err != nil {
+	}
+	return c.pruneBackups(time.Now(), filepath.Base(target))
+}
+
+func (c Config) timestampFormat() string {
+	if c.Backups.Format == "" {
+		return backupFormat
+	}
+	return c.Backups.Format
+}
+
+// backupTime will get the timestamp in a backup name, the modification time is used when the name doesn't match the format
+func (c Config) backupTime(name string, modified time.Time) time.Time {
+	stamp := strings.TrimSuffix(strings.TrimSuffix(name, gzipSuffix), backupSuffix)
+	parsed, err := time.ParseInLocation(c.timestampFormat(), stamp, time.Local)
+	if err != nil {
+		return modified
+	}
+	return parsed
+}
+
+// copyFile will copy (and optionally compress) a file, the target only exists once complete
+func copyFile(source, target string, compress bool) error {
+	b, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return writeBackup(target, b, compress)
+}
+
+func writeBackup(target string, data []byte, compress bool) error {
+	if compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
 			return err
 		}
+		data = buf.Bytes()
 	}
-	format := c.Backups.Format
-	if format == "" {
-		format = "20060102T150405"
+	return backend.WriteFile(target, data)
+}
+
+func readBackup(backup BackupFile) ([]byte, error) {
+	b, err := os.ReadFile(backup.Path)
+	if err != nil || !backup.Compressed {
+		return b, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
 	}
-	target := timestamp.Format(c.Backups.Format)
-	target = filepath.Join(c.Backups.Directory, fmt.Sprintf("%s.%s", target, databaseName))
-	if PathExists(target) {
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// pruneBackups will remove backups older than the duration and/or beyond the count to keep (by the timestamp in
+// the name), the backup just taken is always kept
+func (c Config) pruneBackups(now time.Time, keep string) error {
+	if c.Backups.Duration == "" && c.Backups.Count <= 0 {
 		return nil
 	}
-	if err := exec.Command("cp", c.Database(), target).Run(); err != nil {
+	backups, err := c.ListBackups()
+	if err != nil {
 		return err
 	}
-	return nil
+	var threshold time.Time
+	if c.Backups.Duration != "" {
+		dur, err := durations.Parse(c.Backups.Duration)
+		if err != nil {
+			return err
+		}
+		threshold = now.Add(-dur)
+	}
+	var errs []error
+	for idx, backup := range backups {
+		if backup.Name == keep {
+			continue
+		}
+		expired := !threshold.IsZero() && backup.Time.Before(threshold)
+		if expired || (c.Backups.Count > 0 && idx >= c.Backups.Count) {
+			errs = append(errs, os.Remove(backup.Path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListBackups will get the backups (newest first)
+func (c Config) ListBackups() ([]BackupFile, error) {
+	if c.Backups.Directory == "" {
+		return nil, errors.New("backups are not configured")
+	}
+	entries, err := os.ReadDir(c.Backups.Directory)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var backups []BackupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		compressed := strings.HasSuffix(name, backupSuffix+gzipSuffix)
+		if entry.IsDir() || (!compressed && !strings.HasSuffix(name, backupSuffix)) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, BackupFile{
+			Name:       name,
+			Path:       filepath.Join(c.Backups.Directory, name),
+			Size:       info.Size(),
+			Time:       c.backupTime(name, info.ModTime()),
+			Compressed: compressed,
+		})
+	}
+	slices.SortFunc(backups, func(x, y BackupFile) int {
+		if cmp := y.Time.Compare(x.Time); cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(y.Name, x.Name)
+	})
+	return backups, nil
+}
+
+// RestoreBackup will replace the database with a backup (by name, with or without the file suffix),
+// the backup must load and the current database is backed up first
+func (c Config) RestoreBackup(name string, now time.Time) (BackupFile, error) {
+	backups, err := c.ListBackups()
+	if err != nil {
+		return BackupFile{}, err
+	}
+	idx := slices.IndexFunc(backups, func(b BackupFile) bool {
+		return b.Name == name || strings.TrimSuffix(strings.TrimSuffix(b.Name, gzipSuffix), backupSuffix) == name
+	})
+	if idx < 0 {
+		return BackupFile{}, fmt.Errorf("backup not found: %s", name)
+	}
+	backup := backups[idx]
+	restored := c.Database() + ".restore"
+	defer os.Remove(restored)
+	b, err := readBackup(backup)
+	if err != nil {
+		return backup, err
+	}
+	if err := backend.WriteFile(restored, b); err != nil {
+		return backup, err
+	}
+	store := backend.NewMemoryBased(restored, false, io.Discard)
+	if c.cipher != nil {
+		store.SetCipher(c.cipher)
+	}
+	if err := backend.Load[entities.Stack, entities.Task](store); err != nil {
+		return backup, fmt.Errorf("invalid backup %s: %w", backup.Name, err)
+	}
+	if err := c.Backup(now); err != nil {
+		return backup, err
+	}
+	return backup, os.Rename(restored, c.Database())
 }
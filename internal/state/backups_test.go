@@ -1,6 +1,8 @@
 package state_test
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -26,15 +28,15 @@ func TestBackup(t *testing.T) {
 		t.Errorf("unexpected backup error: %v", err)
 	}
 	children, _ := os.ReadDir(cfg.Backups.Directory)
-	if len(children) != 2 {
+	if len(children) != 2 || children[0].Name() != now.Format("20060102T150405")+".todo.json" {
 		t.Errorf("invalid children: %v", children)
 	}
-	cfg.Backups.Duration = "1s"
+	cfg.Backups.Duration = "1m"
 	if err := cfg.Backup(now.Add(1 * time.Second)); err != nil {
 		t.Errorf("unexpected backup error: %v", err)
 	}
 	children, _ = os.ReadDir(cfg.Backups.Directory)
-	if len(children) != 2 {
+	if len(children) != 3 {
 		t.Errorf("invalid children: %v", children)
 	}
 	cfg.Backups.Format = "2006"
@@ -43,14 +45,110 @@ func TestBackup(t *testing.T) {
 		t.Errorf("unexpected backup error: %v", err)
 	}
 	children, _ = os.ReadDir(cfg.Backups.Directory)
-	if len(children) != 3 {
+	if len(children) != 4 {
 		t.Errorf("invalid children: %v", children)
 	}
 	if err := cfg.Backup(now.Add(1 * time.Second)); err != nil {
 		t.Errorf("unexpected backup error: %v", err)
 	}
 	children, _ = os.ReadDir(cfg.Backups.Directory)
-	if len(children) != 3 {
+	if len(children) != 4 {
 		t.Errorf("invalid children: %v", children)
 	}
 }
+
+func newBackupConfig(t *testing.T) state.Config {
+	cfg := state.Config{}
+	cfg.Data.Directory = t.TempDir()
+	cfg.Backups.Directory = filepath.Join(cfg.Data.Directory, "backups")
+	os.WriteFile(cfg.Database(), []byte(`{"s":{"Node":{"ID":"s","Title":"stack"},"Children":{}}}`), 0o644)
+	return cfg
+}
+
+func TestBackupRetention(t *testing.T) {
+	cfg := newBackupConfig(t)
+	cfg.Backups.Count = 2
+	now := time.Now()
+	for idx := range 4 {
+		when := now.Add(time.Duration(idx-3) * time.Minute)
+		if err := cfg.Backup(when); err != nil {
+			t.Errorf("unexpected backup error: %v", err)
+		}
+		// retention is by the timestamp in the name (not the modification time)
+		os.Chtimes(filepath.Join(cfg.Backups.Directory, when.Format("20060102T150405")+".todo.json"), now, now.Add(-time.Duration(idx)*time.Hour))
+		if backups, _ := cfg.ListBackups(); len(backups) > 2 {
+			t.Errorf("too many backups: %v", backups)
+		}
+	}
+	backups, err := cfg.ListBackups()
+	if err != nil || len(backups) != 2 || backups[0].Name != now.Format("20060102T150405")+".todo.json" {
+		t.Errorf("invalid backups: %v (%v)", backups, err)
+	}
+	old := now.Add(-2 * time.Hour)
+	os.Rename(backups[1].Path, filepath.Join(cfg.Backups.Directory, old.Format("20060102T150405")+".todo.json"))
+	cfg.Backups.Duration = "1h"
+	cfg.Backups.Count = 0
+	if err := cfg.Backup(now); err != nil {
+		t.Errorf("unexpected backup error: %v", err)
+	}
+	if backups, _ := cfg.ListBackups(); len(backups) != 1 {
+		t.Errorf("invalid backups: %v", backups)
+	}
+}
+
+func TestBackupCompress(t *testing.T) {
+	cfg := newBackupConfig(t)
+	cfg.Backups.Compress = true
+	if err := cfg.Backup(time.Now()); err != nil {
+		t.Errorf("unexpected backup error: %v", err)
+	}
+	backups, _ := cfg.ListBackups()
+	if len(backups) != 1 || !backups[0].Compressed {
+		t.Fatalf("invalid backups: %v", backups)
+	}
+	f, _ := os.Open(backups[0].Path)
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("invalid gzip: %v", err)
+	}
+	b, _ := io.ReadAll(r)
+	expect, _ := os.ReadFile(cfg.Database())
+	if string(b) != string(expect) {
+		t.Errorf("invalid backup: %s", b)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	cfg := newBackupConfig(t)
+	cfg.Backups.Compress = true
+	now := time.Now().Add(-time.Minute)
+	if err := cfg.Backup(now); err != nil {
+		t.Errorf("unexpected backup error: %v", err)
+	}
+	original, _ := os.ReadFile(cfg.Database())
+	os.WriteFile(cfg.Database(), []byte(`{}`), 0o644)
+	if _, err := cfg.RestoreBackup("missing", time.Now()); err == nil {
+		t.Error("missing backup restored")
+	}
+	invalid := filepath.Join(cfg.Backups.Directory, "invalid.todo.json")
+	os.WriteFile(invalid, []byte("{"), 0o644)
+	if _, err := cfg.RestoreBackup("invalid.todo.json", time.Now()); err == nil {
+		t.Error("invalid backup restored")
+	}
+	if b, _ := os.ReadFile(cfg.Database()); string(b) != `{}` {
+		t.Errorf("database changed: %s", b)
+	}
+	os.Remove(invalid)
+	backup, err := cfg.RestoreBackup(now.Format("20060102T150405"), time.Now())
+	if err != nil || !backup.Compressed {
+		t.Errorf("invalid restore: %v (%v)", backup, err)
+	}
+	if b, _ := os.ReadFile(cfg.Database()); string(b) != string(original) {
+		t.Errorf("not restored: %s", b)
+	}
+	// the replaced database is kept as a backup
+	if backups, _ := cfg.ListBackups(); len(backups) != 2 {
+		t.Errorf("invalid backups: %v", backups)
+	}
+}
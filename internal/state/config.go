@@ -2,32 +2,112 @@ package state
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/durations"
+	"github.com/enckse/mayhem/internal/entities"
 )
 
-const databaseName = FileName + "json"
+const (
+	databaseName = FileName + "json"
+	archiveName  = FileName + "archive.json"
+	remindName   = FileName + "reminders.json"
+	sessionName  = FileName + "state.json"
+	logName      = "log.txt"
+	templatesDir = "templates"
+	// defaultListen is where the API server listens unless configured
+	defaultListen = "127.0.0.1:7780"
+)
+
+// Path is a named file/directory in use
+type Path struct {
+	Name string
+	Path string
+}
 
 // Config is the overall configuration file
 type Config struct {
 	Data struct {
-		Directory string
-		Pretty    bool
-		NoLock    bool
+		Directory  string
+		Pretty     bool
+		NoLock     bool
+		Git        bool
+		Debounce   string
+		Encryption struct {
+			Enabled bool
+			KeyFile string
+			KeyEnv  string
+		}
 	}
 	Display struct {
 		Finished struct {
 			Since string
 		}
+		Deadlines struct {
+			Soon     string
+			Overdue  string
+			Today    string
+			Upcoming string
+		}
+		Priorities []struct {
+			Name  string
+			Color string
+		}
 	}
 	Backups struct {
 		Directory string
 		Format    string
 		Duration  string
+		Count     int
+		Compress  bool
+		Interval  string
+	}
+	// Profiles override any settings (e.g. [profiles.work.data]), by name
+	Profiles map[string]toml.Primitive
+	// Profile is the name of the profile in use (empty for none)
+	Profile string `toml:"-"`
+	// File is the config file loaded (it may not exist)
+	File  string `toml:"-"`
+	Trash struct {
+		Retention string
+	}
+	Archive struct {
+		Finished string
 	}
+	Server struct {
+		Listen string
+		Token  string
+	}
+	Reminders struct {
+		Lead     string
+		Notifier string
+		Command  string
+		File     string
+		Interval string
+	}
+	Hooks struct {
+		Timeout string
+		Task    struct {
+			Add    string
+			Update string
+			Finish string
+			Delete string
+		}
+		Stack struct {
+			Add    string
+			Update string
+			Delete string
+		}
+	}
+	// cipher is set once unlocked (when encryption is enabled)
+	cipher backend.Cipher
 }
 
 // Database will get the path to the database file
@@ -35,8 +115,124 @@ func (c Config) Database() string {
 	return filepath.Join(c.Data.Directory, databaseName)
 }
 
+// ArchiveDatabase will get the path to the archive database file
+func (c Config) ArchiveDatabase() string {
+	return filepath.Join(c.Data.Directory, archiveName)
+}
+
+// RemindersDatabase will get the path to the file of reminders already sent
+func (c Config) RemindersDatabase() string {
+	return filepath.Join(c.Data.Directory, remindName)
+}
+
+// SessionFile will get the path to the file of the interactive session state
+func (c Config) SessionFile() string {
+	return filepath.Join(c.Data.Directory, sessionName)
+}
+
+// LogFile will get the path to the log file
+func (c Config) LogFile() string {
+	return filepath.Join(c.Data.Directory, logName)
+}
+
+// TemplatesDirectory will get the directory of (stack) templates, next to the config file
+func (c Config) TemplatesDirectory() string {
+	return filepath.Join(filepath.Dir(c.File), templatesDir)
+}
+
+// Paths will get the (named) files and directories in use, for display
+func (c Config) Paths() []Path {
+	settings := c.File
+	if !PathExists(settings) {
+		settings += " (not found, using defaults)"
+	}
+	paths := []Path{
+		{Name: "settings", Path: settings},
+		{Name: "data", Path: c.Data.Directory},
+		{Name: "database", Path: c.Database()},
+		{Name: "archive", Path: c.ArchiveDatabase()},
+		{Name: "reminders", Path: c.RemindersDatabase()},
+		{Name: "session", Path: c.SessionFile()},
+		{Name: "log", Path: c.LogFile()},
+		{Name: "templates", Path: c.TemplatesDirectory()},
+	}
+	if c.Backups.Directory != "" {
+		paths = append(paths, Path{Name: "backups", Path: c.Backups.Directory})
+	}
+	if c.Data.Encryption.Enabled && c.Data.Encryption.KeyFile != "" {
+		paths = append(paths, Path{Name: "key", Path: c.Data.Encryption.KeyFile})
+	}
+	return paths
+}
+
+// HistoryFiles are the data files (relative to the data directory) kept in the git history
+func (c Config) HistoryFiles() []string {
+	return []string{databaseName, archiveName}
+}
+
+// HookCommands will get the configured hook commands by event
+func (c Config) HookCommands() map[string]string {
+	commands := make(map[string]string)
+	for event, command := range map[string]string{
+		entities.TaskAdd:     c.Hooks.Task.Add,
+		entities.TaskUpdate:  c.Hooks.Task.Update,
+		entities.TaskFinish:  c.Hooks.Task.Finish,
+		entities.TaskDelete:  c.Hooks.Task.Delete,
+		entities.StackAdd:    c.Hooks.Stack.Add,
+		entities.StackUpdate: c.Hooks.Stack.Update,
+		entities.StackDelete: c.Hooks.Stack.Delete,
+	} {
+		if strings.TrimSpace(command) != "" {
+			commands[event] = command
+		}
+	}
+	return commands
+}
+
+// Theme will get the display theme (deadline highlighting and priority levels) from the config
+func (c Config) Theme() (display.Theme, error) {
+	deadlines := c.Display.Deadlines
+	theme := display.DefaultTheme()
+	if deadlines.Soon != "" {
+		soon, err := durations.Parse(deadlines.Soon)
+		if err != nil {
+			return theme, err
+		}
+		if soon < 0 {
+			return theme, fmt.Errorf("due soon must not be negative: %s", deadlines.Soon)
+		}
+		theme.DueSoon = soon
+	}
+	theme = display.NewTheme(theme.DueSoon, deadlines.Overdue, deadlines.Today, deadlines.Upcoming)
+	if len(c.Display.Priorities) > entities.PriorityLevels {
+		return theme, fmt.Errorf("too many priorities configured, at most %d allowed", entities.PriorityLevels)
+	}
+	names := make(map[string]bool)
+	for idx, priority := range c.Display.Priorities {
+		level := display.NewPriorityLevel(strings.TrimSpace(priority.Name), priority.Color)
+		theme.Priorities = append(theme.Priorities, level)
+		name := theme.Priority(uint64(idx)).Name
+		if names[name] {
+			return theme, fmt.Errorf("duplicate priority name: %s", name)
+		}
+		names[name] = true
+	}
+	return theme, nil
+}
+
+// ProfileNames will get the (sorted) names of the configured profiles
+func (c Config) ProfileNames() []string {
+	return slices.Sorted(maps.Keys(c.Profiles))
+}
+
 // LoadConfig will load the config from disk
 func LoadConfig(file string) (Config, error) {
+	return LoadProfile(file, "")
+}
+
+// LoadProfile will load the config from disk with a profile applied (an empty profile is none), profiles
+// without a data directory use a directory named after the profile within the data directory
+func LoadProfile(file, profile string) (Config, error) {
 	cfg := file
 	if cfg == "" {
 		var err error
@@ -46,35 +242,77 @@ func LoadConfig(file string) (Config, error) {
 		}
 		cfg = filepath.Join(cfg, "settings.toml")
 	}
-	config := Config{}
+	config := Config{File: cfg}
 	if PathExists(cfg) {
 		meta, err := toml.DecodeFile(cfg, &config)
 		if err != nil {
 			return config, err
 		}
+		// every profile is decoded so that all are validated
+		root := config
+		for _, name := range config.ProfileNames() {
+			profiled := root
+			if err := meta.PrimitiveDecode(config.Profiles[name], &profiled); err != nil {
+				return config, fmt.Errorf("profile %s: %w", name, err)
+			}
+			if name != profile {
+				continue
+			}
+			config = profiled
+			config.Profile = name
+			if !meta.IsDefined("profiles", name, "data", "directory") {
+				dir, err := dataDirectory(root.Data.Directory)
+				if err != nil {
+					return config, err
+				}
+				config.Data.Directory = filepath.Join(dir, name)
+				if err := os.MkdirAll(config.Data.Directory, os.ModePerm); err != nil {
+					return config, err
+				}
+			}
+		}
 
 		undecoded := meta.Undecoded()
 		if len(undecoded) > 0 {
 			return config, fmt.Errorf("unknown config TOML fields: %v", undecoded)
 		}
 	}
-	if config.Data.Directory == "" {
-		d, err := detectDir("XDG_CACHE_HOME", "DATA_DIR", ".cache")
-		if err != nil {
-			return config, err
-		}
-		config.Data.Directory = d
+	if profile != "" && config.Profile != profile {
+		return config, fmt.Errorf("unknown profile: %s", profile)
 	}
-	const isHome = "~"
-	if strings.HasPrefix(config.Data.Directory, isHome) {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return config, err
-		}
-		config.Data.Directory = strings.Replace(config.Data.Directory, isHome, home, 1)
+	dir, err := dataDirectory(config.Data.Directory)
+	if err != nil {
+		return config, err
+	}
+	config.Data.Directory = dir
+	if config.Data.Encryption.KeyFile, err = expandHome(config.Data.Encryption.KeyFile); err != nil {
+		return config, err
 	}
 	if config.Backups.Directory != "" {
 		config.Backups.Directory = filepath.Join(config.Data.Directory, config.Backups.Directory)
 	}
+	if config.Server.Listen == "" {
+		config.Server.Listen = defaultListen
+	}
 	return config, nil
 }
+
+// dataDirectory will get the data directory (the default when not set)
+func dataDirectory(dir string) (string, error) {
+	if dir == "" {
+		return detectDir("XDG_CACHE_HOME", "DATA_DIR", ".cache")
+	}
+	return expandHome(dir)
+}
+
+func expandHome(path string) (string, error) {
+	const isHome = "~"
+	if !strings.HasPrefix(path, isHome) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path, err
+	}
+	return strings.Replace(path, isHome, home, 1), nil
+}
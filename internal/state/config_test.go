@@ -1,9 +1,13 @@
 package state_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/state"
 )
 
@@ -15,6 +19,14 @@ func TestConfigDatabase(t *testing.T) {
 	}
 }
 
+func TestConfigArchiveDatabase(t *testing.T) {
+	c := state.Config{}
+	c.Data.Directory = "xyz"
+	if c.ArchiveDatabase() != "xyz/todo.archive.json" {
+		t.Errorf("invalid archive file: %s", c.ArchiveDatabase())
+	}
+}
+
 func TestConfigFile(t *testing.T) {
 	cfg := testConfig("settings.toml", t)
 	if strings.Contains(cfg.Data.Directory, "~") {
@@ -23,6 +35,20 @@ func TestConfigFile(t *testing.T) {
 	if cfg.Backups.Directory == "" {
 		t.Error("invalid backups dir")
 	}
+	if cfg.File != "settings.toml" {
+		t.Errorf("invalid file: %s", cfg.File)
+	}
+	paths := cfg.Paths()
+	if len(paths) != 9 || paths[0].Path != "settings.toml" || paths[8].Name != "backups" {
+		t.Errorf("invalid paths: %v", paths)
+	}
+	if cfg.TemplatesDirectory() != "templates" {
+		t.Errorf("invalid templates: %s", cfg.TemplatesDirectory())
+	}
+	cfg.File = "missing.toml"
+	if !strings.HasSuffix(cfg.Paths()[0].Path, "(not found, using defaults)") {
+		t.Errorf("invalid paths: %v", cfg.Paths())
+	}
 }
 
 func TestConfigEnv(t *testing.T) {
@@ -31,6 +57,9 @@ func TestConfigEnv(t *testing.T) {
 	if c.Data.Directory != "testdata/mayhem" {
 		t.Errorf("invalid data dir: %v", c.Data.Directory)
 	}
+	if c.Server.Listen != "127.0.0.1:7780" {
+		t.Errorf("invalid listen: %v", c.Server.Listen)
+	}
 }
 
 func testConfig(file string, t *testing.T) state.Config {
@@ -51,3 +80,106 @@ func TestConfigBadTOML(t *testing.T) {
 		t.Errorf("invalid load: %v", err)
 	}
 }
+
+func TestConfigHookCommands(t *testing.T) {
+	c := state.Config{}
+	if len(c.HookCommands()) != 0 {
+		t.Error("no hooks should be configured")
+	}
+	c.Hooks.Task.Finish = "notify-send done"
+	c.Hooks.Stack.Add = " "
+	commands := c.HookCommands()
+	if len(commands) != 1 || commands["task.finish"] != "notify-send done" {
+		t.Errorf("invalid hooks: %v", commands)
+	}
+}
+
+func TestConfigTheme(t *testing.T) {
+	c := state.Config{}
+	theme, err := c.Theme()
+	if err != nil || theme.DueSoon != 3*24*time.Hour {
+		t.Errorf("invalid default theme: %v %v", theme.DueSoon, err)
+	}
+	c.Display.Deadlines.Soon = "1w"
+	c.Display.Deadlines.Overdue = "9"
+	theme, err = c.Theme()
+	if err != nil || theme.DueSoon != 7*24*time.Hour || theme.Overdue.GetForeground() != lipgloss.Color("9") {
+		t.Errorf("invalid theme: %v %v", theme.DueSoon, err)
+	}
+	for _, bad := range []string{"x", "-1d"} {
+		c.Display.Deadlines.Soon = bad
+		if _, err := c.Theme(); err == nil {
+			t.Errorf("expected error: %s", bad)
+		}
+	}
+}
+
+func TestConfigPriorities(t *testing.T) {
+	c := state.Config{}
+	c.Display.Priorities = append(c.Display.Priorities, struct {
+		Name  string
+		Color string
+	}{Name: "Someday", Color: "8"}, struct {
+		Name  string
+		Color string
+	}{})
+	theme, err := c.Theme()
+	if err != nil || theme.Priority(0).Name != "Someday" || theme.Priority(1).Name != "1" || theme.Priority(0).Style.GetForeground() != lipgloss.Color("8") {
+		t.Errorf("invalid priorities: %v %v", theme.Priorities, err)
+	}
+	c.Display.Priorities[1].Name = "Someday"
+	if _, err := c.Theme(); err == nil || err.Error() != "duplicate priority name: Someday" {
+		t.Errorf("expected duplicate error: %v", err)
+	}
+	c.Display.Priorities = make([]struct {
+		Name  string
+		Color string
+	}, 6)
+	if _, err := c.Theme(); err == nil {
+		t.Error("expected too many priorities error")
+	}
+}
+
+func TestConfigProfiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "settings.toml")
+	os.WriteFile(file, []byte(`
+[data]
+directory = "`+dir+`"
+pretty = true
+
+[profiles.work.data]
+pretty = false
+
+[profiles.home.data]
+directory = "`+filepath.Join(dir, "elsewhere")+`"
+
+[profiles.home.backups]
+directory = "backups"
+`), 0o644)
+	cfg, err := state.LoadProfile(file, "")
+	if err != nil || cfg.Profile != "" || cfg.Data.Directory != dir || !cfg.Data.Pretty || cfg.Backups.Directory != "" {
+		t.Errorf("invalid default: %v (%v)", cfg, err)
+	}
+	if names := cfg.ProfileNames(); strings.Join(names, ",") != "home,work" {
+		t.Errorf("invalid names: %v", names)
+	}
+	cfg, err = state.LoadProfile(file, "work")
+	if err != nil || cfg.Profile != "work" || cfg.Data.Directory != filepath.Join(dir, "work") || cfg.Data.Pretty {
+		t.Errorf("invalid work: %v (%v)", cfg, err)
+	}
+	if !state.PathExists(cfg.Data.Directory) {
+		t.Error("profile directory not created")
+	}
+	cfg, err = state.LoadProfile(file, "home")
+	if err != nil || cfg.Data.Directory != filepath.Join(dir, "elsewhere") || !cfg.Data.Pretty || cfg.Backups.Directory != filepath.Join(dir, "elsewhere", "backups") {
+		t.Errorf("invalid home: %v (%v)", cfg, err)
+	}
+	if _, err := state.LoadProfile(file, "other"); err == nil || err.Error() != "unknown profile: other" {
+		t.Errorf("invalid unknown profile: %v", err)
+	}
+	os.WriteFile(file, []byte("[profiles.bad.data]\nunknown = 1\n"), 0o644)
+	if _, err := state.LoadProfile(file, ""); err == nil {
+		t.Error("invalid profile fields should fail")
+	}
+}
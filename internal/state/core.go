@@ -3,6 +3,7 @@ package state
 
 import (
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -22,9 +23,36 @@ type (
 		DB     backend.Store
 		Config Config
 		Screen *display.Screen
+		Logger io.Writer
+		// Switch will close the context and open one for another profile, the (still or re)opened context is
+		// returned with any error (nil when unsupported)
+		Switch func(profile string) (*Context, error)
+		// OnArchive will wrap the archive store when it is loaded, e.g. to commit its changes (nil when unused)
+		OnArchive func(backend.Store) backend.Store
+		archive   backend.Store
 	}
 )
 
+// Archive will get the archive store, it is only loaded on first use
+func (c *Context) Archive() (backend.Store, error) {
+	if c.archive != nil {
+		return c.archive, nil
+	}
+	logger := c.Logger
+	if logger == nil {
+		logger = io.Discard
+	}
+	store, err := c.Config.OpenStore(c.Config.ArchiveDatabase(), logger)
+	if err != nil {
+		return nil, err
+	}
+	c.archive = store
+	if c.OnArchive != nil {
+		c.archive = c.OnArchive(store)
+	}
+	return c.archive, nil
+}
+
 // PathExists indicates if a path exists
 func PathExists(path string) bool {
 	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
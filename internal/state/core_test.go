@@ -3,6 +3,7 @@ package state_test
 import (
 	"testing"
 
+	"github.com/enckse/mayhem/internal/backend"
 	"github.com/enckse/mayhem/internal/state"
 )
 
@@ -14,3 +15,27 @@ func TestPathExists(t *testing.T) {
 		t.Error("invalid file")
 	}
 }
+
+func TestArchive(t *testing.T) {
+	ctx := &state.Context{}
+	ctx.Config.Data.Directory = "testdata"
+	a, err := ctx.Archive()
+	if err != nil || a == nil {
+		t.Errorf("invalid archive: %v", err)
+	}
+	b, _ := ctx.Archive()
+	if a != b {
+		t.Error("archive should only be opened once")
+	}
+	wrapped := 0
+	ctx = &state.Context{OnArchive: func(store backend.Store) backend.Store {
+		wrapped++
+		return store
+	}}
+	ctx.Config.Data.Directory = "testdata"
+	ctx.Archive()
+	ctx.Archive()
+	if wrapped != 1 {
+		t.Errorf("archive should be wrapped once: %d", wrapped)
+	}
+}
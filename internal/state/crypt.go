@@ -0,0 +1,124 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+// keyEnv is the environment variable holding the key unless configured
+const keyEnv = envPrefix + "KEY"
+
+// Cipher will get the cipher for the data files, the key is read from the key file, the environment or the prompt (in that order)
+func (c Config) Cipher(prompt func() (string, error)) (backend.Cipher, error) {
+	key, err := c.key(prompt)
+	if err != nil {
+		return nil, err
+	}
+	return backend.NewPassphrase(key)
+}
+
+func (c Config) key(prompt func() (string, error)) (string, error) {
+	if file := c.Data.Encryption.KeyFile; file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	env := c.Data.Encryption.KeyEnv
+	if env == "" {
+		env = keyEnv
+	}
+	if key := os.Getenv(env); key != "" {
+		return key, nil
+	}
+	if prompt == nil {
+		return "", fmt.Errorf("no key available (set %s or a key file)", env)
+	}
+	return prompt()
+}
+
+// Unlock will setup the cipher when encryption is enabled
+func (c *Config) Unlock(prompt func() (string, error)) error {
+	if !c.Data.Encryption.Enabled {
+		return nil
+	}
+	cipher, err := c.Cipher(prompt)
+	if err != nil {
+		return err
+	}
+	c.cipher = cipher
+	return nil
+}
+
+// OpenStore will open a data file (decrypting/encrypting it when unlocked)
+func (c Config) OpenStore(file string, logger io.Writer) (*backend.MemoryBased, error) {
+	return entities.OpenStore(file, c.Data.Pretty, c.cipher, logger)
+}
+
+// Convert will encrypt (or decrypt) the data files and backups, files already converted are skipped
+func (c Config) Convert(cipher backend.Cipher, encrypt bool) ([]string, error) {
+	convert := func(data []byte) ([]byte, bool, error) {
+		if backend.IsEncrypted(data) == encrypt {
+			return nil, false, nil
+		}
+		if encrypt {
+			b, err := cipher.Encrypt(data)
+			return b, true, err
+		}
+		b, err := cipher.Decrypt(data)
+		return b, true, err
+	}
+	var converted []string
+	for _, file := range []string{c.Database(), c.ArchiveDatabase()} {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return converted, err
+		}
+		b, changed, err := convert(b)
+		if err != nil {
+			return converted, fmt.Errorf("%s: %w", file, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := backend.WriteFile(file, b); err != nil {
+			return converted, err
+		}
+		converted = append(converted, file)
+	}
+	if c.Backups.Directory == "" {
+		return converted, nil
+	}
+	backups, err := c.ListBackups()
+	if err != nil {
+		return converted, err
+	}
+	for _, backup := range backups {
+		b, err := readBackup(backup)
+		if err != nil {
+			return converted, err
+		}
+		b, changed, err := convert(b)
+		if err != nil {
+			return converted, fmt.Errorf("%s: %w", backup.Path, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := writeBackup(backup.Path, b, backup.Compressed); err != nil {
+			return converted, err
+		}
+		converted = append(converted, backup.Path)
+	}
+	return converted, nil
+}
@@ -0,0 +1,95 @@
+package state_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+)
+
+func TestCipherKey(t *testing.T) {
+	cfg := state.Config{}
+	t.Setenv("MAYHEM_KEY", "")
+	if _, err := cfg.Cipher(nil); err == nil {
+		t.Error("no key available")
+	}
+	prompted := false
+	prompt := func() (string, error) {
+		prompted = true
+		return "prompt", nil
+	}
+	if _, err := cfg.Cipher(prompt); err != nil || !prompted {
+		t.Errorf("key should be prompted: %v", err)
+	}
+	prompted = false
+	t.Setenv("MAYHEM_KEY", "env")
+	if _, err := cfg.Cipher(prompt); err != nil || prompted {
+		t.Errorf("key should be from the environment: %v", err)
+	}
+	cfg.Data.Encryption.KeyFile = filepath.Join(t.TempDir(), "missing")
+	if _, err := cfg.Cipher(prompt); err == nil {
+		t.Error("missing key file")
+	}
+	if err := cfg.Unlock(nil); err != nil {
+		t.Errorf("encryption is not enabled: %v", err)
+	}
+	cfg.Data.Encryption.Enabled = true
+	if err := cfg.Unlock(nil); err == nil {
+		t.Error("missing key file")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cfg := newBackupConfig(t)
+	keyFile := filepath.Join(cfg.Data.Directory, "key")
+	os.WriteFile(keyFile, []byte("secret\n"), 0o600)
+	cfg.Data.Encryption.KeyFile = keyFile
+	cfg.Backups.Compress = true
+	past := time.Now().Add(-time.Hour)
+	if err := cfg.Backup(past); err != nil {
+		t.Errorf("unexpected backup error: %v", err)
+	}
+	if backups, _ := cfg.ListBackups(); len(backups) == 1 {
+		os.Chtimes(backups[0].Path, past, past)
+	}
+	cipher, err := cfg.Cipher(nil)
+	if err != nil {
+		t.Fatalf("invalid cipher: %v", err)
+	}
+	files, err := cfg.Convert(cipher, true)
+	if err != nil || len(files) != 2 {
+		t.Errorf("invalid encrypt: %v (%v)", files, err)
+	}
+	if files, _ := cfg.Convert(cipher, true); len(files) != 0 {
+		t.Errorf("already encrypted: %v", files)
+	}
+	if _, err := cfg.OpenStore(cfg.Database(), nil); !errors.Is(err, backend.ErrEncrypted) {
+		t.Errorf("encrypted data opened while locked: %v", err)
+	}
+	cfg.Data.Encryption.Enabled = true
+	if err := cfg.Unlock(nil); err != nil {
+		t.Fatalf("invalid unlock: %v", err)
+	}
+	store, err := cfg.OpenStore(cfg.Database(), os.Stderr)
+	if err != nil || len(entities.FetchStacks(store)) != 1 {
+		t.Errorf("invalid open: %v", err)
+	}
+	backups, _ := cfg.ListBackups()
+	if len(backups) != 1 || backups[0].Time.After(past.Add(time.Second)) {
+		t.Errorf("backup time should be kept: %v", backups)
+	}
+	if _, err := cfg.RestoreBackup(backups[0].Name, time.Now()); err != nil {
+		t.Errorf("encrypted backup should restore: %v", err)
+	}
+	if files, err := cfg.Convert(cipher, false); err != nil || len(files) != 3 {
+		t.Errorf("invalid decrypt: %v (%v)", files, err)
+	}
+	if b, _ := os.ReadFile(cfg.Database()); backend.IsEncrypted(b) {
+		t.Error("not decrypted")
+	}
+}
@@ -0,0 +1,41 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Session is the interactive state kept between runs
+type Session struct {
+	StackID    string `json:",omitempty"`
+	TaskID     string `json:",omitempty"`
+	Focus      string `json:",omitempty"`
+	Unfiltered bool   `json:",omitempty"`
+	HideHelp   bool   `json:",omitempty"`
+	// Offset is the details scroll offset (and FocusIndex the focused details block)
+	Offset     int `json:",omitempty"`
+	FocusIndex int `json:",omitempty"`
+}
+
+// LoadSession will load the session state from a file (a missing file is an empty session)
+func LoadSession(file string) (Session, error) {
+	var session Session
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return session, nil
+		}
+		return session, err
+	}
+	return session, json.Unmarshal(b, &session)
+}
+
+// Save will write the session state to a file
+func (s Session) Save(file string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0o644)
+}
@@ -0,0 +1,27 @@
+package state_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/enckse/mayhem/internal/state"
+)
+
+func TestSession(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.state.json")
+	session, err := state.LoadSession(file)
+	if err != nil || session.StackID != "" {
+		t.Errorf("invalid empty session: %v (%v)", session, err)
+	}
+	session.StackID = "abc"
+	session.TaskID = "xyz"
+	session.Focus = "detail"
+	session.HideHelp = true
+	session.Offset = 3
+	if err := session.Save(file); err != nil {
+		t.Errorf("invalid save: %v", err)
+	}
+	if loaded, err := state.LoadSession(file); err != nil || loaded != session {
+		t.Errorf("invalid session: %v (%v)", loaded, err)
+	}
+}
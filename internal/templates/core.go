@@ -0,0 +1,201 @@
+// Package templates handles stacks (and their tasks) kept as TOML templates to create repeatedly
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/durations"
+	"github.com/enckse/mayhem/internal/entities"
+)
+
+const extension = ".toml"
+
+type (
+	// Template is a stack and its tasks
+	Template struct {
+		// Stack is the title of the stack created
+		Stack string `toml:"stack"`
+		// Priority is the priority of tasks that do not set one
+		Priority uint64 `toml:"priority,omitzero"`
+		Tasks    []Task `toml:"tasks"`
+	}
+
+	// Task is a templated task, the deadline is relative to when the template is applied (e.g. +2d)
+	Task struct {
+		Title     string  `toml:"title"`
+		Notes     string  `toml:"notes,omitempty"`
+		Priority  *uint64 `toml:"priority,omitzero"`
+		Deadline  string  `toml:"deadline,omitempty"`
+		Estimate  string  `toml:"estimate,omitempty"`
+		Reminders string  `toml:"reminders,omitempty"`
+	}
+)
+
+// List will get the names of the templates in a directory (none when it does not exist)
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), extension); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Load will load (and validate) a template by name
+func Load(dir, name string) (Template, error) {
+	file, err := path(dir, name)
+	if err != nil {
+		return Template{}, err
+	}
+	var t Template
+	meta, err := toml.DecodeFile(file, &t)
+	if err != nil {
+		return t, err
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return t, fmt.Errorf("unknown template TOML fields: %v", undecoded)
+	}
+	if _, err := t.NewTasks(time.Now()); err != nil {
+		return t, fmt.Errorf("template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// Save will write the template by name (replacing any template of that name)
+func (t Template) Save(dir, name string) error {
+	file, err := path(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(f).Encode(t); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func path(dir, name string) (string, error) {
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid template name: %s", name)
+	}
+	return filepath.Join(dir, name+extension), nil
+}
+
+// NewTasks will get the (new) tasks of the template, deadlines relative to now
+func (t Template) NewTasks(now time.Time) ([]entities.Task, error) {
+	var tasks []entities.Task
+	for _, templated := range t.Tasks {
+		task := entities.NewTask()
+		task.Title = templated.Title
+		task.Notes = templated.Notes
+		task.Priority = t.Priority
+		if templated.Priority != nil {
+			task.Priority = *templated.Priority
+		}
+		if err := task.Validate(); err != nil {
+			return nil, fmt.Errorf("task '%s': %w", templated.Title, err)
+		}
+		if templated.Deadline != "" {
+			offset, err := durations.Parse(templated.Deadline)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", templated.Title, err)
+			}
+			task.Deadline = now.Add(offset)
+		}
+		if templated.Estimate != "" {
+			estimate, err := durations.Parse(templated.Estimate)
+			if err != nil || estimate < 0 {
+				return nil, fmt.Errorf("task '%s': invalid estimate: %s", templated.Title, templated.Estimate)
+			}
+			task.Estimate = estimate
+		}
+		reminders, err := durations.ParseList(templated.Reminders)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': %w", templated.Title, err)
+		}
+		task.Reminders = reminders
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Apply will add the tasks of the template to a stack, deadlines relative to now
+func (t Template) Apply(store backend.Store, stack entities.Stack, now time.Time) ([]entities.Task, error) {
+	tasks, err := t.NewTasks(now)
+	if err != nil {
+		return nil, err
+	}
+	store.Batch(func() {
+		for idx, task := range tasks {
+			task.StackID = stack.ID
+			tasks[idx] = task.Save(store).(entities.Task)
+		}
+	})
+	return tasks, nil
+}
+
+// FromStack will create a template from a stack (its tasks in the order of the stack), deadlines are relative to
+// the earliest created task
+func FromStack(stack entities.Stack) Template {
+	t := FromTasks(stack.Tasks, stack.Sort)
+	t.Stack = stack.Title
+	return t
+}
+
+// FromTasks will create a template of tasks (without a stack) in the order of a sort strategy, deadlines are
+// relative to the earliest created task
+func FromTasks(tasks []entities.Task, strategy entities.SortStrategy) Template {
+	tasks = slices.Clone(tasks)
+	entities.SortTasksBy(tasks, strategy)
+	var t Template
+	var since time.Time
+	for _, task := range tasks {
+		if since.IsZero() || task.Created.Before(since) {
+			since = task.Created
+		}
+	}
+	for _, task := range tasks {
+		priority := task.Priority
+		templated := Task{
+			Title:     task.Title,
+			Notes:     task.Notes,
+			Priority:  &priority,
+			Reminders: durations.FormatList(task.Reminders),
+		}
+		if !task.Deadline.IsZero() {
+			templated.Deadline = durations.Format(task.Deadline.Sub(since))
+			if !strings.HasPrefix(templated.Deadline, "-") {
+				templated.Deadline = "+" + templated.Deadline
+			}
+		}
+		if task.Estimate > 0 {
+			templated.Estimate = durations.Format(task.Estimate)
+		}
+		t.Tasks = append(t.Tasks, templated)
+	}
+	return t
+}
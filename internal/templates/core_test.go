@@ -0,0 +1,154 @@
+package templates_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/templates"
+)
+
+const release = `stack = "Release"
+priority = 2
+
+[[tasks]]
+title = "tag"
+deadline = "+2d"
+priority = 4
+
+[[tasks]]
+title = "announce"
+notes = "mailing list"
+deadline = "+1w"
+estimate = "30m"
+reminders = "1d, 1h"
+
+[[tasks]]
+title = "celebrate"
+`
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	if names, err := templates.List(filepath.Join(dir, "missing")); err != nil || len(names) != 0 {
+		t.Errorf("invalid list: %v %v", names, err)
+	}
+	os.WriteFile(filepath.Join(dir, "release.toml"), []byte(release), 0o644)
+	os.WriteFile(filepath.Join(dir, "onboard.toml"), []byte(release), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(release), 0o644)
+	os.Mkdir(filepath.Join(dir, "dir.toml"), 0o755)
+	if names, err := templates.List(dir); err != nil || !slices.Equal(names, []string{"onboard", "release"}) {
+		t.Errorf("invalid list: %v %v", names, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"release":  release,
+		"unknown":  "stack = \"x\"\ncolor = \"red\"",
+		"deadline": "[[tasks]]\ntitle = \"x\"\ndeadline = \"soon\"",
+		"priority": "[[tasks]]\ntitle = \"x\"\npriority = 9",
+		"title":    "[[tasks]]\nnotes = \"x\"",
+		"estimate": "[[tasks]]\ntitle = \"x\"\nestimate = \"-1h\"",
+		"remind":   "[[tasks]]\ntitle = \"x\"\nreminders = \"later\"",
+	} {
+		os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0o644)
+		_, err := templates.Load(dir, name)
+		if (err == nil) != (name == "release") {
+			t.Errorf("invalid load of %s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"missing", "", "../release", ".hidden"} {
+		if _, err := templates.Load(dir, name); err == nil {
+			t.Errorf("%s should not load", name)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "release.toml"), []byte(release), 0o644)
+	tmpl, err := templates.Load(dir, "release")
+	if err != nil || tmpl.Stack != "Release" || len(tmpl.Tasks) != 3 {
+		t.Fatalf("invalid template: %v %v", tmpl, err)
+	}
+	var buf bytes.Buffer
+	db := backend.NewMemoryBased("", false, &buf)
+	stack := entities.NewStack(db)
+	now := time.Now()
+	tasks, err := tmpl.Apply(db, stack, now)
+	if err != nil || len(tasks) != 3 {
+		t.Fatalf("invalid apply: %v %v", tasks, err)
+	}
+	saved, _ := entities.FindStack(db, stack.ID)
+	if len(saved.Tasks) != 3 {
+		t.Errorf("invalid tasks: %v", saved.Tasks)
+	}
+	byTitle := make(map[string]entities.Task)
+	for _, task := range saved.Tasks {
+		byTitle[task.Title] = task
+	}
+	if task := byTitle["tag"]; !task.Deadline.Equal(now.Add(48*time.Hour)) || task.Priority != 4 {
+		t.Errorf("invalid task: %v", task)
+	}
+	if task := byTitle["announce"]; task.Notes != "mailing list" || task.Priority != 2 || task.Estimate != 30*time.Minute || len(task.Reminders) != 2 {
+		t.Errorf("invalid task: %v", task)
+	}
+	if task := byTitle["celebrate"]; !task.Deadline.IsZero() || task.Priority != 2 {
+		t.Errorf("invalid task: %v", task)
+	}
+	if _, err := (templates.Template{Tasks: []templates.Task{{Title: ""}}}).Apply(db, stack, now); err == nil {
+		t.Error("invalid template should not apply")
+	}
+}
+
+func TestFromStack(t *testing.T) {
+	created := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	stack := entities.Stack{Title: "Release", Sort: entities.SortManual}
+	first := entities.Task{Title: "tag", Priority: 3, Deadline: created.Add(50 * time.Hour), Estimate: time.Hour, Ordinal: 1}
+	first.Created = created.Add(time.Hour)
+	second := entities.Task{Title: "announce", Notes: "list", Reminders: []time.Duration{time.Hour}, Ordinal: 2}
+	second.Created = created
+	third := entities.Task{Title: "early", Deadline: created.Add(-time.Hour), Ordinal: 3}
+	third.Created = created.Add(time.Minute)
+	// tasks are kept in the order of the stack (not as loaded)
+	stack.Tasks = []entities.Task{third, first, second}
+	tmpl := templates.FromStack(stack)
+	if tmpl.Stack != "Release" || len(tmpl.Tasks) != 3 {
+		t.Fatalf("invalid template: %v", tmpl)
+	}
+	if task := tmpl.Tasks[0]; task.Deadline != "+2d2h" || *task.Priority != 3 || task.Estimate != "1h" {
+		t.Errorf("invalid task: %v", task)
+	}
+	if task := tmpl.Tasks[1]; task.Deadline != "" || task.Notes != "list" || task.Reminders != "1h" || *task.Priority != 0 {
+		t.Errorf("invalid task: %v", task)
+	}
+	if task := tmpl.Tasks[2]; task.Deadline != "-1h" {
+		t.Errorf("invalid task: %v", task)
+	}
+	dir := filepath.Join(t.TempDir(), "templates")
+	if err := tmpl.Save(dir, "release"); err != nil {
+		t.Fatalf("invalid save: %v", err)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, "release.toml"))
+	if !strings.Contains(string(b), `deadline = "+2d2h"`) || !strings.Contains(string(b), "[[tasks]]") {
+		t.Errorf("invalid file: %s", string(b))
+	}
+	loaded, err := templates.Load(dir, "release")
+	if err != nil || len(loaded.Tasks) != 3 || loaded.Tasks[1].Title != "announce" {
+		t.Errorf("invalid reload: %v %v", loaded, err)
+	}
+	if err := tmpl.Save(dir, "a/b"); err == nil {
+		t.Error("invalid name should not save")
+	}
+	tasks := templates.FromTasks(stack.Tasks, entities.SortDefault)
+	if tasks.Stack != "" || len(tasks.Tasks) != 3 || tasks.Tasks[0].Title != "early" || stack.Tasks[0].Title != "early" {
+		t.Errorf("invalid task template: %v", tasks)
+	}
+}
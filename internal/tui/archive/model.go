@@ -0,0 +1,140 @@
+// Package archive handles browsing and un-archiving archived stacks/tasks
+package archive
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/inputs/timepicker"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+// maxItems is how many archive entries are rendered at once
+const maxItems = 10
+
+type (
+	// Model is the archive view
+	Model struct {
+		context    *state.Context
+		archive    backend.Store
+		items      []item
+		focusIndex int
+		changed    bool
+	}
+
+	item struct {
+		isStack bool
+		stack   entities.Stack
+		task    entities.Task
+	}
+)
+
+// New will create a new archive view
+func New(ctx *state.Context) (tea.Model, error) {
+	archive, err := ctx.Archive()
+	if err != nil {
+		return nil, err
+	}
+	m := Model{context: ctx, archive: archive}
+	m.load()
+	return m, nil
+}
+
+func (m *Model) load() {
+	m.items = []item{}
+	for _, s := range entities.FetchArchive(m.archive) {
+		m.items = append(m.items, item{isStack: true, stack: s})
+		for _, t := range s.Tasks {
+			m.items = append(m.items, item{stack: s, task: t})
+		}
+	}
+	if m.focusIndex >= len(m.items) {
+		m.focusIndex = len(m.items) - 1
+	}
+	if m.focusIndex < 0 {
+		m.focusIndex = 0
+	}
+}
+
+// Init will init the model
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update will update the model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Mappings.Return):
+			if m.changed {
+				return m, messages.MainGoToWith("refresh")
+			}
+			return m, messages.MainGoTo
+		case key.Matches(msg, keys.Mappings.Exit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Mappings.Up):
+			if m.focusIndex > 0 {
+				m.focusIndex--
+			}
+		case key.Matches(msg, keys.Mappings.Down):
+			if m.focusIndex < len(m.items)-1 {
+				m.focusIndex++
+			}
+		case key.Matches(msg, keys.Mappings.Unarchive):
+			if len(m.items) == 0 {
+				return m, nil
+			}
+			selected := m.items[m.focusIndex]
+			if selected.isStack {
+				entities.UnarchiveStack(m.context.DB, m.archive, selected.stack)
+			} else {
+				entities.UnarchiveTask(m.context.DB, m.archive, selected.task)
+			}
+			m.changed = true
+			m.load()
+		}
+	}
+	return m, nil
+}
+
+// View will display the model
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(display.HighlightedTextStyle.Render("Archive"))
+	b.WriteString("\n\n")
+	if len(m.items) == 0 {
+		b.WriteString(display.PlaceHolderStyle.Render("Archive is empty"))
+		return b.String()
+	}
+	var res []string
+	start, end := display.ListWindow(len(m.items), m.focusIndex, maxItems)
+	for i := start; i < end; i++ {
+		entry := m.items[i]
+		prefix := "  "
+		if i == m.focusIndex {
+			prefix = "» "
+		}
+		var text string
+		if entry.isStack {
+			text = fmt.Sprintf("%s (%d task(s))", entry.stack.Title, len(entry.stack.Tasks))
+		} else {
+			status := "open"
+			if !entry.task.Finished.IsZero() {
+				status = "finished " + timepicker.FormatTime(entry.task.Finished, true)
+			}
+			text = fmt.Sprintf("    %-30s %s", entry.task.Title, status)
+		}
+		res = append(res, lipgloss.NewStyle().Foreground(display.InputFormColor).Bold(true).Render(prefix+text))
+	}
+	b.WriteString(lipgloss.JoinVertical(lipgloss.Left, res...))
+	return b.String()
+}
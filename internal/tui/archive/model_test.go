@@ -0,0 +1,54 @@
+package archive_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/archive"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+func TestModel(t *testing.T) {
+	var buf bytes.Buffer
+	os.RemoveAll("testdata")
+	ctx := &state.Context{}
+	ctx.Config.Data.Directory = "testdata"
+	ctx.DB = backend.NewMemoryBased("", false, &buf)
+	stack := entities.Stack{ID: "s", Title: "stack"}
+	stack.Save(ctx.DB)
+	task := entities.Task{ID: "t", Title: "task", StackID: "s", Finished: time.Now()}
+	task.Save(ctx.DB)
+	m, err := archive.New(ctx)
+	if err != nil || m.Init() != nil {
+		t.Errorf("invalid model: %v", err)
+	}
+	if !strings.Contains(m.View(), "Archive is empty") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	store, _ := ctx.Archive()
+	entities.ArchiveTasks(ctx.DB, store, []entities.Task{task})
+	m, _ = archive.New(ctx)
+	v := m.View()
+	if !strings.Contains(v, "stack (1 task(s))") || !strings.Contains(v, "task") {
+		t.Errorf("invalid view: %s", v)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if len(entities.FetchArchive(store)) != 0 {
+		t.Error("task not unarchived")
+	}
+	if stacks := entities.FetchStacks(ctx.DB); len(stacks) != 1 || len(stacks[0].Tasks) != 1 {
+		t.Errorf("task not restored: %v", stacks)
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if val, ok := cmd().(messages.Main); !ok || val.Value != "refresh" {
+		t.Error("invalid exit")
+	}
+}
@@ -21,13 +21,47 @@ const (
 	TaskPriorityIndex
 	// TaskDeadlineIndex is the deadline index for task fields (indexed)
 	TaskDeadlineIndex
+	// TaskEstimateIndex is the estimate (and tracked time) index for task fields (indexed)
+	TaskEstimateIndex
+	// TaskRemindersIndex is the (deadline) reminders index for task fields (indexed)
+	TaskRemindersIndex
+	// TaskHistoryIndex is the (read-only) history index for task details (indexed)
+	TaskHistoryIndex
 )
 
 const (
 	// TaskLastIndex is the last known task item (index)
-	TaskLastIndex = TaskDeadlineIndex
+	TaskLastIndex = TaskHistoryIndex
 	// IsDelete is a delete command
 	IsDelete = "delete"
 	// IsMove is a move command
 	IsMove = "move"
+	// IsNest is a stack nesting (move under another stack) command
+	IsNest = "nest"
+	// IsToggle is a (bulk) toggle command
+	IsToggle = "toggle"
+	// IsPriority is a priority change command
+	IsPriority = "priority"
+	// IsShift is a deadline shift command
+	IsShift = "shift"
+	// IsTrash is the trash view
+	IsTrash = "trash"
+	// IsArchive is the archive view
+	IsArchive = "archive"
+	// IsProfile is a profile switch command
+	IsProfile = "profile"
+	// IsCommand is the command palette
+	IsCommand = "command"
+	// IsTemplate is a new stack (or tasks) from a template command
+	IsTemplate = "template"
+	// IsSaveTemplate is a save stack (or tasks) as a template command
+	IsSaveTemplate = "save-template"
+	// IsClone is a (bulk) task clone command
+	IsClone = "clone"
+	// IsCloneStack is a stack clone command (picking how finished tasks are cloned)
+	IsCloneStack = "clone-stack"
+	// IsCloneShift is a stack clone command (picking how deadlines are shifted)
+	IsCloneShift = "clone-shift"
+	// IsRollOver is carrying over (or unplanning) unfinished tasks planned before today
+	IsRollOver = "roll-over"
 )
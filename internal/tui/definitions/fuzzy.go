@@ -0,0 +1,43 @@
+package definitions
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fuzzy will score how well a query matches text (case insensitive, the query must appear in order),
+// a higher score is a better match and a negative score is no match
+func Fuzzy(query, text string) int {
+	q := []rune(strings.ToLower(strings.TrimSpace(query)))
+	t := []rune(strings.ToLower(strings.TrimSpace(text)))
+	if len(q) == 0 {
+		return 0
+	}
+	if string(q) == string(t) {
+		return 100
+	}
+	score := 0
+	matched := 0
+	prev := -2
+	for idx, r := range t {
+		if matched == len(q) {
+			break
+		}
+		if r != q[matched] {
+			continue
+		}
+		score++
+		if idx == prev+1 {
+			score += 2
+		}
+		if idx == 0 || !unicode.IsLetter(t[idx-1]) && !unicode.IsDigit(t[idx-1]) {
+			score += 3
+		}
+		prev = idx
+		matched++
+	}
+	if matched < len(q) {
+		return -1
+	}
+	return score
+}
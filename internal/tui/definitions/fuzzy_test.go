@@ -0,0 +1,25 @@
+package definitions_test
+
+import (
+	"testing"
+
+	"github.com/enckse/mayhem/internal/tui/definitions"
+)
+
+func TestFuzzy(t *testing.T) {
+	if definitions.Fuzzy("", "abc") != 0 {
+		t.Error("empty query should match")
+	}
+	if definitions.Fuzzy("xyz", "abc") >= 0 || definitions.Fuzzy("ba", "abc") >= 0 {
+		t.Error("out of order query should not match")
+	}
+	exact := definitions.Fuzzy("Move", "move")
+	prefix := definitions.Fuzzy("mov", "move-up")
+	scattered := definitions.Fuzzy("mv", "move")
+	if exact <= prefix || prefix <= scattered || scattered < 0 {
+		t.Errorf("invalid scores: %d %d %d", exact, prefix, scattered)
+	}
+	if definitions.Fuzzy("md", "move-down") <= definitions.Fuzzy("md", "mode") {
+		t.Error("word starts should score higher")
+	}
+}
@@ -20,12 +20,19 @@ const isConfirm = "y"
 // textinput.Model doesn't implement tea.Model interface
 type Confirmation struct {
 	customInputType string
+	prompt          string
 }
 
 // NewConfirmation creates a new confirmation model item
 func NewConfirmation() tea.Model {
+	return NewPromptConfirmation(definitions.IsDelete, "Do you wish to proceed with deletion?")
+}
+
+// NewPromptConfirmation creates a confirmation for an input type with a custom prompt
+func NewPromptConfirmation(inputType, prompt string) tea.Model {
 	m := Confirmation{
-		customInputType: definitions.IsDelete,
+		customInputType: inputType,
+		prompt:          prompt,
 	}
 
 	return m
@@ -58,5 +65,5 @@ func (m Confirmation) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // View will handle rendering the view
 func (m Confirmation) View() string {
 	// Can't just render textinput.Value(), otherwise cursor blinking wouldn't work
-	return lipgloss.NewStyle().Foreground(display.HighlightedBackgroundColor).Padding(1, 0).Render("Do you wish to proceed with deletion? (" + isConfirm + "/n): ")
+	return lipgloss.NewStyle().Foreground(display.HighlightedBackgroundColor).Padding(1, 0).Render(m.prompt + " (" + isConfirm + "/n): ")
 }
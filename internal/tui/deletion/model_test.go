@@ -30,3 +30,11 @@ func TestConfirmation(t *testing.T) {
 		t.Errorf("invalid deletion: %s", v)
 	}
 }
+
+func TestPromptConfirmation(t *testing.T) {
+	obj := deletion.NewPromptConfirmation("toggle", "Toggle 3 tasks?")
+	v := obj.View()
+	if !strings.Contains(v, "Toggle 3 tasks? (y/n)") {
+		t.Errorf("invalid prompt: %s", v)
+	}
+}
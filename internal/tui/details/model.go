@@ -4,12 +4,14 @@ package details
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/durations"
 	"github.com/enckse/mayhem/internal/entities"
 	"github.com/enckse/mayhem/internal/tui/definitions"
 	"github.com/enckse/mayhem/internal/tui/inputs/timepicker"
@@ -30,10 +32,13 @@ type (
 	}
 
 	scrollData struct {
-		title    int
-		notes    int
-		priority int
-		deadline int
+		title     int
+		notes     int
+		priority  int
+		deadline  int
+		estimate  int
+		reminders int
+		history   int
 	}
 )
 
@@ -85,6 +90,15 @@ func (m Box) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				scrollDistance = m.scrollData.priority
 				m.Previous()
 			case definitions.TaskDeadlineIndex:
+				scrollDistance = m.scrollData.deadline
+				m.Previous()
+			case definitions.TaskEstimateIndex:
+				scrollDistance = m.scrollData.estimate
+				m.Previous()
+			case definitions.TaskRemindersIndex:
+				scrollDistance = m.scrollData.reminders
+				m.Previous()
+			case definitions.TaskHistoryIndex:
 				m.Previous()
 			}
 
@@ -102,6 +116,15 @@ func (m Box) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				scrollDistance = m.scrollData.priority
 				m.Next()
 			case definitions.TaskDeadlineIndex:
+				scrollDistance = m.scrollData.deadline
+				m.Next()
+			case definitions.TaskEstimateIndex:
+				scrollDistance = m.scrollData.estimate
+				m.Next()
+			case definitions.TaskRemindersIndex:
+				scrollDistance = m.scrollData.reminders
+				m.Next()
+			case definitions.TaskHistoryIndex:
 				m.ViewPort.GotoTop()
 				m.Start()
 				return m, nil
@@ -164,12 +187,41 @@ func (m *Box) Start() {
 	m.renderContent()
 }
 
+// Select will move to the component at a line of the box (as scrolled), it indicates if there is one
+func (m *Box) Select(line int) bool {
+	if line < 0 || line >= m.ViewPort.Height {
+		return false
+	}
+	line += m.ViewPort.YOffset
+	heights := []int{
+		m.scrollData.title,
+		m.scrollData.notes,
+		m.scrollData.priority,
+		m.scrollData.deadline,
+		m.scrollData.estimate,
+		m.scrollData.reminders,
+		m.scrollData.history,
+	}
+	for idx, height := range heights {
+		if line < height {
+			m.FocusIndex = idx
+			m.renderContent()
+			return true
+		}
+		line -= height
+	}
+	return false
+}
+
 func (m *Box) renderContent() {
 	content := []string{
 		m.titleBlock(),
 		m.notesBlock(),
 		m.priorityBlock(),
 		m.deadlineBlock(),
+		m.estimateBlock(),
+		m.remindersBlock(),
+		m.historyBlock(),
 	}
 
 	view := lipgloss.JoinVertical(lipgloss.Left, content...)
@@ -220,7 +272,12 @@ func (m *Box) priorityBlock() string {
 	var b strings.Builder
 	isFocused := (m.FocusIndex == definitions.TaskPriorityIndex)
 	newBlock(&b, "Priority", isFocused)
-	fmt.Fprintf(&b, "%d", m.taskData.Priority)
+	level := m.screen.Theme.Priority(m.taskData.Priority)
+	name := level.Style.Render(level.Name)
+	if level.Name != fmt.Sprintf("%d", m.taskData.Priority) {
+		name = fmt.Sprintf("%s (%d)", name, m.taskData.Priority)
+	}
+	b.WriteString(name)
 
 	data := m.screen.ItemContainerStyle(isFocused).Render(m.screen.DetailsItemStyle(isFocused).Render(b.String()))
 	m.scrollData.priority = lipgloss.Height(data)
@@ -236,6 +293,17 @@ func (m *Box) deadlineBlock() string {
 		b.WriteString("Not Scheduled")
 	} else {
 		b.WriteString(timepicker.FormatTime(m.taskData.Deadline, true))
+		if m.taskData.Finished.IsZero() {
+			theme := m.screen.Theme
+			switch m.taskData.Due(time.Now(), theme.DueSoon) {
+			case entities.Overdue:
+				b.WriteString(" " + theme.Overdue.Render("(overdue)"))
+			case entities.DueToday:
+				b.WriteString(" " + theme.DueToday.Render("(due today)"))
+			case entities.DueSoon:
+				b.WriteString(" " + theme.Upcoming.Render("(due soon)"))
+			}
+		}
 	}
 
 	data := m.screen.ItemContainerStyle(isFocused).Render(m.screen.DetailsItemStyle(isFocused).Render(b.String()))
@@ -243,6 +311,59 @@ func (m *Box) deadlineBlock() string {
 	return data
 }
 
+func (m *Box) estimateBlock() string {
+	var b strings.Builder
+	isFocused := (m.FocusIndex == definitions.TaskEstimateIndex)
+	newBlock(&b, "Time", isFocused)
+
+	estimate := "-"
+	if m.taskData.Estimate > 0 {
+		estimate = durations.Format(m.taskData.Estimate)
+	}
+	fmt.Fprintf(&b, "Estimate: %s\n", estimate)
+	fmt.Fprintf(&b, "Tracked:  %s", durations.Format(m.taskData.Tracked(time.Time{}, time.Now())))
+	if m.taskData.Running() {
+		b.WriteString(" (running)")
+	}
+
+	data := m.screen.ItemContainerStyle(isFocused).Render(m.screen.DetailsItemStyle(isFocused).Render(b.String()))
+	m.scrollData.estimate = lipgloss.Height(data)
+	return data
+}
+
+func (m *Box) remindersBlock() string {
+	var b strings.Builder
+	isFocused := (m.FocusIndex == definitions.TaskRemindersIndex)
+	newBlock(&b, "Reminders", isFocused)
+
+	if len(m.taskData.Reminders) == 0 {
+		b.WriteString("Default")
+	} else {
+		b.WriteString(durations.FormatList(m.taskData.Reminders) + " before deadline")
+	}
+
+	data := m.screen.ItemContainerStyle(isFocused).Render(m.screen.DetailsItemStyle(isFocused).Render(b.String()))
+	m.scrollData.reminders = lipgloss.Height(data)
+	return data
+}
+
+func (m *Box) historyBlock() string {
+	var b strings.Builder
+	isFocused := (m.FocusIndex == definitions.TaskHistoryIndex)
+	newBlock(&b, "History", isFocused)
+
+	fmt.Fprintf(&b, "Created: %s\n", timepicker.FormatTime(m.taskData.Created, true))
+	fmt.Fprintf(&b, "Updated: %s", timepicker.FormatTime(m.taskData.Updated, true))
+	for i := len(m.taskData.History) - 1; i >= 0; i-- {
+		change := m.taskData.History[i]
+		fmt.Fprintf(&b, "\n\n%s\n  %s: %s → %s", timepicker.FormatTime(change.Time, true), change.Field, change.Old, change.New)
+	}
+
+	data := m.screen.ItemContainerStyle(isFocused).Render(m.screen.DetailsItemStyle(isFocused).Render(b.String()))
+	m.scrollData.history = lipgloss.Height(data)
+	return data
+}
+
 func (m *Box) footerView() string {
 	scrollInfoStyle := display.FooterContainerStyle.Width(m.ViewPort.Width).Align(lipgloss.Right)
 	info := display.FooterInfoStyle.Render(fmt.Sprintf("%3.f%%", m.ViewPort.ScrollPercent()*100))
@@ -70,11 +70,11 @@ func TestDetails(t *testing.T) {
 		t.Errorf("invalid focus: %d", b.FocusIndex)
 	}
 	b.End()
-	if b.FocusIndex != 3 {
+	if b.FocusIndex != 6 {
 		t.Errorf("invalid focus: %d", b.FocusIndex)
 	}
 	b.Previous()
-	if b.FocusIndex != 2 {
+	if b.FocusIndex != 5 {
 		t.Errorf("invalid focus: %d", b.FocusIndex)
 	}
 	b.Start()
@@ -82,3 +82,50 @@ func TestDetails(t *testing.T) {
 		t.Errorf("invalid focus: %d", b.FocusIndex)
 	}
 }
+
+func TestHistoryBlock(t *testing.T) {
+	s := display.NewScreen()
+	s.Width = 200
+	s.Table.ViewHeight = 100
+	b := details.NewBox(s)
+	task := entities.Task{}
+	task.History = []entities.Change{{Field: "Priority", Old: "0", New: "2"}}
+	b.Build(task, false)
+	b.End()
+	v := b.View()
+	if !strings.Contains(v, "History") || !strings.Contains(v, "Priority: 0 → 2") {
+		t.Errorf("invalid view: %s", v)
+	}
+}
+
+func TestPriorityBlock(t *testing.T) {
+	s := display.NewScreen()
+	s.Width = 200
+	s.Table.ViewHeight = 100
+	s.Theme.Priorities = []display.PriorityLevel{{}, {}, {Name: "Normal"}}
+	b := details.NewBox(s)
+	b.Build(entities.Task{Priority: 2}, false)
+	if v := b.View(); !strings.Contains(v, "Normal (2)") {
+		t.Errorf("invalid view: %s", v)
+	}
+	b.Build(entities.Task{Priority: 1}, false)
+	if v := b.View(); strings.Contains(v, "(1)") {
+		t.Errorf("invalid view: %s", v)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	s := display.NewScreen()
+	s.Width = 200
+	b := details.NewBox(s)
+	b.Build(entities.Task{Title: "title"}, false)
+	if b.Select(-1) || b.Select(s.Table.ViewHeight) {
+		t.Error("invalid selection outside of the box")
+	}
+	if !b.Select(0) || b.FocusIndex != 0 {
+		t.Errorf("invalid selection: %d", b.FocusIndex)
+	}
+	if !b.Select(s.Table.ViewHeight-1) || b.FocusIndex == 0 {
+		t.Errorf("invalid selection: %d", b.FocusIndex)
+	}
+}
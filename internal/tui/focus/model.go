@@ -0,0 +1,168 @@
+// Package focus is the focus view, the tasks planned for today (from any stack) and nothing else
+package focus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/inputs/timepicker"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+const (
+	// screenChrome is the title and footer lines around the tasks
+	screenChrome = 6
+	// cardHeight is the lines of a task (border, title and details)
+	cardHeight = 4
+	// maxWidth is the widest a task is rendered
+	maxWidth = 80
+)
+
+// Model is the focus view
+type Model struct {
+	context    *state.Context
+	tasks      []entities.Task
+	stacks     map[string]string
+	focusIndex int
+	changed    bool
+	width      int
+	height     int
+}
+
+// New will create the focus view of the tasks planned for today
+func New(ctx *state.Context, width, height int) tea.Model {
+	m := Model{context: ctx, width: width, height: height}
+	m.load("")
+	return m
+}
+
+// load will reload the tasks planned for today, keeping the focus on a task (by ID) when still planned
+func (m *Model) load(focusID string) {
+	stacks := entities.ListStacks(m.context.DB)
+	m.stacks = make(map[string]string)
+	for _, stack := range stacks {
+		m.stacks[stack.ID] = stack.Title
+	}
+	m.tasks = entities.PlannedTasks(stacks, time.Now())
+	if idx := slices.IndexFunc(m.tasks, func(t entities.Task) bool { return t.ID == focusID }); idx >= 0 {
+		m.focusIndex = idx
+	}
+	m.focusIndex = max(min(m.focusIndex, len(m.tasks)-1), 0)
+}
+
+// Init will init the model
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) exit() tea.Cmd {
+	if m.changed {
+		return messages.MainGoToWith("refresh")
+	}
+	return messages.MainGoTo
+}
+
+// Update will update the model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Mappings.Focus), key.Matches(msg, keys.Mappings.Return):
+			return m, m.exit()
+		case key.Matches(msg, keys.Mappings.Exit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Mappings.Up):
+			if m.focusIndex > 0 {
+				m.focusIndex--
+			}
+		case key.Matches(msg, keys.Mappings.Down):
+			if m.focusIndex < len(m.tasks)-1 {
+				m.focusIndex++
+			}
+		case key.Matches(msg, keys.Mappings.Toggle):
+			if len(m.tasks) == 0 {
+				return m, nil
+			}
+			task := m.tasks[m.focusIndex].ToggleFinished(m.context.DB, time.Now())
+			m.changed = true
+			m.load(task.ID)
+		case key.Matches(msg, keys.Mappings.Plan):
+			if len(m.tasks) == 0 {
+				return m, nil
+			}
+			task := m.tasks[m.focusIndex]
+			task.Planned = time.Time{}
+			task.Save(m.context.DB)
+			m.changed = true
+			m.load("")
+		}
+	}
+	return m, nil
+}
+
+// View will display the model
+func (m Model) View() string {
+	now := time.Now()
+	var finished int
+	for _, task := range m.tasks {
+		if !task.Finished.IsZero() {
+			finished++
+		}
+	}
+	title := lipgloss.JoinVertical(lipgloss.Left,
+		display.HighlightedTextStyle.Render(fmt.Sprintf("Today · %s", now.Format("Monday, January 2"))),
+		display.PlaceHolderStyle.Render(fmt.Sprintf(" %d of %d done", finished, len(m.tasks))),
+	)
+	footer := display.PlaceHolderStyle.Render(fmt.Sprintf("'↑'/'↓' move · %s finish · %s unplan · %s/%s close",
+		keys.Mappings.Toggle.Help().Key, keys.Mappings.Plan.Help().Key, keys.Mappings.Focus.Help().Key, keys.Mappings.Return.Help().Key))
+	var body string
+	if len(m.tasks) == 0 {
+		body = display.PlaceHolderStyle.Padding(1, 2).Render(fmt.Sprintf("Nothing planned for today, %s in the task table plans the marked (or selected) tasks", keys.Mappings.Plan.Help().Key))
+	} else {
+		var cards []string
+		start, end := display.ListWindow(len(m.tasks), m.focusIndex, max((m.height-screenChrome)/cardHeight, 1))
+		for i := start; i < end; i++ {
+			cards = append(cards, m.card(m.tasks[i], i == m.focusIndex, now))
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, cards...)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", body, "", footer)
+}
+
+// card will render a task (title then its stack, deadline and priority)
+func (m Model) card(task entities.Task, focused bool, now time.Time) string {
+	theme := m.context.Screen.Theme
+	style := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(display.UnfocusedColor).Padding(0, 2).Width(min(max(m.width-4, 20), maxWidth))
+	if focused {
+		style = style.Border(lipgloss.ThickBorder()).BorderForeground(display.InputFormColor)
+	}
+	title := lipgloss.NewStyle().Bold(true).Render("○ " + task.Title)
+	if !task.Finished.IsZero() {
+		title = lipgloss.NewStyle().Strikethrough(true).Faint(true).Render("✔ " + task.Title)
+	}
+	info := []string{m.stacks[task.StackID]}
+	if !task.Deadline.IsZero() {
+		deadline := "due " + timepicker.FormatTime(task.Deadline, true)
+		switch task.Due(now, theme.DueSoon) {
+		case entities.Overdue:
+			deadline = theme.Overdue.Render("overdue " + timepicker.FormatTime(task.Deadline, true))
+		case entities.DueToday:
+			deadline = theme.DueToday.Render(deadline)
+		}
+		info = append(info, deadline)
+	}
+	info = append(info, theme.Priority(task.Priority).Name)
+	return style.Render(lipgloss.JoinVertical(lipgloss.Left, title, display.PlaceHolderStyle.Render(strings.Join(info, " · "))))
+}
@@ -0,0 +1,105 @@
+package focus_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/focus"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+func newContext() *state.Context {
+	var buf bytes.Buffer
+	ctx := &state.Context{Screen: display.NewScreen()}
+	ctx.DB = backend.NewMemoryBased("", false, &buf)
+	now := time.Now()
+	for _, stack := range []entities.Stack{{ID: "work", Title: "Work"}, {ID: "home", Title: "Home"}} {
+		stack.Save(ctx.DB)
+	}
+	for _, task := range []entities.Task{
+		{ID: "a", Title: "write report", StackID: "work", Planned: now, Deadline: now.Add(-time.Hour)},
+		{ID: "b", Title: "water plants", StackID: "home", Planned: now},
+		{ID: "c", Title: "not today", StackID: "home"},
+		{ID: "d", Title: "yesterday", StackID: "home", Planned: now.AddDate(0, 0, -1)},
+	} {
+		task.Save(ctx.DB)
+	}
+	return ctx
+}
+
+func key(value string) tea.KeyMsg {
+	switch value {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEscape}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(value)}
+}
+
+func planned(ctx *state.Context) []entities.Task {
+	return entities.PlannedTasks(entities.ListStacks(ctx.DB), time.Now())
+}
+
+func TestView(t *testing.T) {
+	ctx := newContext()
+	m := focus.New(ctx, 100, 40)
+	if m.Init() != nil {
+		t.Error("invalid init")
+	}
+	view := m.View()
+	for _, text := range []string{"Today", "0 of 2 done", "write report", "water plants", "Work · overdue", "Home"} {
+		if !strings.Contains(view, text) {
+			t.Errorf("missing %s: %s", text, view)
+		}
+	}
+	if strings.Contains(view, "not today") || strings.Contains(view, "yesterday") {
+		t.Errorf("only tasks planned for today should be shown: %s", view)
+	}
+	empty := &state.Context{Screen: display.NewScreen(), DB: backend.NewMemoryBased("", false, &bytes.Buffer{})}
+	if view := focus.New(empty, 100, 40).View(); !strings.Contains(view, "Nothing planned for today") {
+		t.Errorf("invalid empty view: %s", view)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := newContext()
+	var m tea.Model = focus.New(ctx, 100, 40)
+	_, cmd := m.Update(key("esc"))
+	if msg, ok := cmd().(messages.Main); !ok || msg.Value != "" {
+		t.Errorf("should close without changes: %v", msg)
+	}
+	entities.ToggleTimer(ctx.DB, planned(ctx)[0], time.Now())
+	m, _ = m.Update(key("tab"))
+	tasks := planned(ctx)
+	if len(tasks) != 2 || tasks[1].ID != "a" || tasks[1].Finished.IsZero() || len(tasks[1].History) == 0 {
+		t.Errorf("task should be finished: %v", tasks)
+	}
+	if tasks[1].Running() {
+		t.Errorf("finishing should stop the timer: %v", tasks[1])
+	}
+	if !strings.Contains(m.View(), "1 of 2 done") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	m, _ = m.Update(key("j"))
+	m, _ = m.Update(key("tab"))
+	if tasks := planned(ctx); !tasks[0].Finished.IsZero() || !tasks[1].Finished.IsZero() {
+		t.Errorf("task should be unfinished: %v", tasks)
+	}
+	m, _ = m.Update(key("k"))
+	m, _ = m.Update(key("d"))
+	if tasks := planned(ctx); len(tasks) != 1 || tasks[0].ID != "b" {
+		t.Errorf("task should be unplanned: %v", tasks)
+	}
+	_, cmd = m.Update(key("F"))
+	if msg, ok := cmd().(messages.Main); !ok || msg.Value != "refresh" {
+		t.Errorf("should close with a refresh: %v", msg)
+	}
+}
@@ -5,17 +5,16 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/enckse/mayhem/internal/tui/keys"
 )
 
 // Model is the underlying help model
 type Model struct {
 	help help.Model
-	keys keys.Map
+	keys help.KeyMap
 }
 
-// NewModel will create a new help model
-func NewModel(keys keys.Map) Model {
+// NewModel will create a new help model (for keys.Map, keys.Bindings)
+func NewModel(keys help.KeyMap) Model {
 	return Model{
 		keys: keys,
 		help: help.New(),
@@ -42,5 +41,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // View will handle view rendering
 func (m Model) View() string {
 	style := lipgloss.NewStyle().MarginTop(1)
+	if m.keys == nil {
+		// an empty model (no keys) shows no help
+		return style.Render("")
+	}
 	return style.Render(m.help.View(m.keys))
 }
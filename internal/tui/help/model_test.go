@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/state"
 	"github.com/enckse/mayhem/internal/tui/help"
 	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
 )
 
 func TestModel(t *testing.T) {
@@ -19,3 +21,49 @@ func TestModel(t *testing.T) {
 		t.Error("view failed")
 	}
 }
+
+func TestEmptyModel(t *testing.T) {
+	if strings.TrimSpace(help.Model{}.View()) != "" {
+		t.Error("empty model should have no help")
+	}
+}
+
+func TestScreen(t *testing.T) {
+	paths := []state.Path{{Name: "settings", Path: "/a/settings.toml"}}
+	content := help.Content(keys.TaskContext, paths)
+	if !strings.Contains(content, "/a/settings.toml") {
+		t.Errorf("invalid content: %s", content)
+	}
+	for _, text := range []string{"tasks (current)", "time picker", "clear the date/time", "(toggle)", "priority <priority>"} {
+		if !strings.Contains(content, text) {
+			t.Errorf("missing %s: %s", text, content)
+		}
+	}
+	if strings.Index(content, "tasks (current)") > strings.Index(content, "stacks") {
+		t.Error("current context should be first")
+	}
+	var m tea.Model = help.NewScreen(keys.StackContext, paths, 80, 10)
+	if m.Init() != nil {
+		t.Error("invalid init")
+	}
+	if !strings.Contains(m.View(), "stacks (current)") || strings.Contains(m.View(), "/a/settings.toml") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 12})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	for range 500 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if !strings.Contains(m.View(), "/a/settings.toml") || !strings.Contains(m.View(), "100%") {
+		t.Errorf("should scroll: %s", m.View())
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	if _, ok := cmd().(messages.Main); !ok {
+		t.Error("should close")
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if _, ok := cmd().(messages.Main); !ok {
+		t.Error("should close")
+	}
+}
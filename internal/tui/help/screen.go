@@ -0,0 +1,104 @@
+package help
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+// screenChrome is the title and footer lines around the (scrolling) help
+const screenChrome = 4
+
+// Screen is the full screen help (all bindings by context and the paths in use)
+type Screen struct {
+	viewport viewport.Model
+}
+
+// NewScreen will create the help screen, the current context is listed first
+func NewScreen(current keys.Context, paths []state.Path, width, height int) Screen {
+	m := Screen{viewport: viewport.New(width, max(height-screenChrome, 1))}
+	m.viewport.SetContent(Content(current, paths))
+	return m
+}
+
+// Content will render the help (bindings by context then the paths)
+func Content(current keys.Context, paths []state.Path) string {
+	groups := []keys.Group{}
+	for _, group := range keys.Groups {
+		if group.Context == current {
+			group.Title += " (current)"
+			groups = append([]keys.Group{group}, groups...)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	header := lipgloss.NewStyle().Foreground(display.InputFormColor).Bold(true)
+	binding := lipgloss.NewStyle().Width(14)
+	var b strings.Builder
+	for _, group := range groups {
+		b.WriteString(header.Render(group.Title))
+		b.WriteString("\n")
+		for _, action := range keys.Actions {
+			if action.Context != group.Context {
+				continue
+			}
+			name := action.Name
+			if action.Args != "" {
+				name = fmt.Sprintf("%s %s", name, action.Args)
+			}
+			fmt.Fprintf(&b, "  %s%s %s\n", binding.Render(action.Binding.Help().Key), display.PlaceHolderStyle.Render(fmt.Sprintf("(%s)", name)), action.Description)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(header.Render("paths"))
+	b.WriteString("\n")
+	for _, path := range paths {
+		fmt.Fprintf(&b, "  %s%s\n", binding.Render(path.Name), path.Path)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Init is the model init
+func (m Screen) Init() tea.Cmd {
+	return nil
+}
+
+// Update will update the model
+func (m Screen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-screenChrome, 1)
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Mappings.Help), key.Matches(msg, keys.Mappings.Return):
+			return m, messages.MainGoTo
+		case key.Matches(msg, keys.Mappings.Exit):
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View will display the model
+func (m Screen) View() string {
+	footer := fmt.Sprintf("%3.f%% · '↑'/'↓'/'pgup'/'pgdown' scroll · %s/%s close", m.viewport.ScrollPercent()*100, keys.Mappings.Help.Help().Key, keys.Mappings.Return.Help().Key)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		display.HighlightedTextStyle.Render("Help"),
+		"",
+		m.viewport.View(),
+		"",
+		display.PlaceHolderStyle.Render(footer),
+	)
+}
@@ -2,6 +2,8 @@
 package lists
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -80,6 +82,34 @@ func (m Selector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// Value will get the focused option
+func (m Selector) Value() definitions.KeyValue {
+	if len(m.options) == 0 {
+		return definitions.KeyValue{}
+	}
+	return m.options[m.focusIndex]
+}
+
+// Match will find the option best matching a value (by key or, fuzzily, by value)
+func (m Selector) Match(value string) (definitions.KeyValue, bool) {
+	value = strings.TrimSpace(value)
+	for _, option := range m.options {
+		if option.Key == value && option.Key != "" {
+			return option, true
+		}
+	}
+	best, score := -1, -1
+	for idx, option := range m.options {
+		if s := definitions.Fuzzy(value, option.Value); s > score {
+			best, score = idx, s
+		}
+	}
+	if best < 0 || value == "" {
+		return definitions.KeyValue{}, false
+	}
+	return m.options[best], true
+}
+
 // View will show the model
 func (m Selector) View() string {
 	var res []string
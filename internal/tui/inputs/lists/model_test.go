@@ -40,3 +40,29 @@ func TestInput(t *testing.T) {
 		t.Error("invalid result")
 	}
 }
+
+func TestValue(t *testing.T) {
+	obj := lists.NewSelector([]definitions.KeyValue{{Key: "1", Value: "abc"}, {Key: "2", Value: "xyz"}}, "abc", messages.MainGoToWith)
+	obj, _ = obj.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if obj.(lists.Selector).Value().Key != "2" {
+		t.Errorf("invalid value: %v", obj.(lists.Selector).Value())
+	}
+	empty := lists.NewSelector(nil, "", messages.MainGoToWith).(lists.Selector)
+	if empty.Value() != (definitions.KeyValue{}) {
+		t.Error("no options should have no value")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	obj := lists.NewSelector([]definitions.KeyValue{{Key: "1", Value: "Home"}, {Key: "2", Value: "  Work"}, {Key: "3", Value: "Workshop"}}, "", messages.MainGoToWith).(lists.Selector)
+	for value, expect := range map[string]string{"2": "2", "work": "2", "wshop": "3", "hm": "1"} {
+		if option, ok := obj.Match(value); !ok || option.Key != expect {
+			t.Errorf("invalid match for %s: %v", value, option)
+		}
+	}
+	for _, value := range []string{"", "xyz"} {
+		if _, ok := obj.Match(value); ok {
+			t.Errorf("%s should not match", value)
+		}
+	}
+}
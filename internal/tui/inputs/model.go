@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/durations"
 	"github.com/enckse/mayhem/internal/entities"
 	"github.com/enckse/mayhem/internal/state"
 	"github.com/enckse/mayhem/internal/tui/definitions"
@@ -28,8 +29,10 @@ type (
 		focusIndex    int
 		data          entities.Entity
 		isStacks      bool
+		isFull        bool
 		fieldMap      map[int]field
 		isInvalid     bool
+		invalidIndex  int
 		invalidPrompt string
 		helpKeys      keys.Map
 		context       *state.Context
@@ -82,6 +85,18 @@ var (
 			prompt:   "Task Deadline",
 			helpKeys: keys.TimePickerMappings,
 		},
+		definitions.TaskEstimateIndex: {
+			name:             "Estimate",
+			prompt:           "Task Estimate",
+			helpKeys:         keys.TextInputMappings,
+			validationPrompt: "Estimate must be a duration (e.g. 1h30m)❗",
+		},
+		definitions.TaskRemindersIndex: {
+			name:             "Reminders",
+			prompt:           "Task Reminders (before deadline, empty for defaults)",
+			helpKeys:         keys.TextInputMappings,
+			validationPrompt: "Reminders must be durations (e.g. 1d, 1h)❗",
+		},
 	}
 )
 
@@ -95,6 +110,28 @@ func NewTaskForm(data entities.Entity, fieldIndex int, ctx *state.Context) Form
 	return newForm(false, data, fieldIndex, ctx)
 }
 
+// NewFullTaskForm generates a form showing every (editable) task field, saved together
+func NewFullTaskForm(data entities.Entity, ctx *state.Context) Form {
+	task := data.(entities.Task)
+	m := Form{
+		data:     data,
+		isFull:   true,
+		fieldMap: make(map[int]field),
+		context:  ctx,
+	}
+	for fieldIndex := definitions.TaskTitleIndex; fieldIndex <= definitions.TaskRemindersIndex; fieldIndex++ {
+		m.fieldMap[fieldIndex] = newTaskField(fieldIndex, task, ctx)
+	}
+	if task.Deadline.IsZero() {
+		// a new task has no deadline unless one is picked
+		deadline := m.fieldMap[definitions.TaskDeadlineIndex]
+		deadline.model = timepicker.NewUnset(time.Now())
+		m.fieldMap[definitions.TaskDeadlineIndex] = deadline
+	}
+	m.setFocus(definitions.TaskTitleIndex)
+	return m
+}
+
 func newForm(isStack bool, data entities.Entity, fieldIndex int, ctx *state.Context) Form {
 	var m Form
 	if isStack {
@@ -102,10 +139,10 @@ func newForm(isStack bool, data entities.Entity, fieldIndex int, ctx *state.Cont
 		m = Form{
 			data:       data,
 			focusIndex: fieldIndex,
-			fieldMap:   stackFields,
+			fieldMap:   make(map[int]field),
 		}
 
-		targetField := m.fieldMap[fieldIndex]
+		targetField := stackFields[fieldIndex]
 		stack := data.(entities.Stack)
 
 		switch fieldIndex {
@@ -119,33 +156,10 @@ func newForm(isStack bool, data entities.Entity, fieldIndex int, ctx *state.Cont
 		m = Form{
 			data:       data,
 			focusIndex: fieldIndex,
-			fieldMap:   taskFields,
+			fieldMap:   make(map[int]field),
 		}
 
-		targetField := m.fieldMap[fieldIndex]
-		task := data.(entities.Task)
-
-		switch fieldIndex {
-		case definitions.TaskTitleIndex:
-			targetField.model = text.New(task.Title, "", 60, messages.FormGoToWith)
-		case definitions.TaskNotesIndex:
-			targetField.model = textarea.New(task.Notes, ctx.Screen)
-		case definitions.TaskPriorityIndex:
-			opts := []definitions.KeyValue{
-				{Value: "0"},
-				{Value: "1"},
-				{Value: "2"},
-				{Value: "3"},
-				{Value: entities.MaxPriority},
-			}
-			targetField.model = lists.NewSelector(opts, fmt.Sprintf("%d", task.Priority), messages.FormGoToWith)
-		case definitions.TaskDeadlineIndex:
-			if task.Deadline.IsZero() {
-				targetField.model = timepicker.New(time.Now())
-			} else {
-				targetField.model = timepicker.New(task.Deadline)
-			}
-		}
+		targetField := newTaskField(fieldIndex, data.(entities.Task), ctx)
 		m.helpKeys = targetField.helpKeys
 		m.fieldMap[fieldIndex] = targetField
 	}
@@ -155,6 +169,51 @@ func newForm(isStack bool, data entities.Entity, fieldIndex int, ctx *state.Cont
 	return m
 }
 
+func newTaskField(fieldIndex int, task entities.Task, ctx *state.Context) field {
+	targetField := taskFields[fieldIndex]
+	switch fieldIndex {
+	case definitions.TaskTitleIndex:
+		targetField.model = text.New(task.Title, "", 60, messages.FormGoToWith)
+	case definitions.TaskNotesIndex:
+		targetField.model = textarea.New(task.Notes, ctx.Screen)
+	case definitions.TaskPriorityIndex:
+		theme := ctx.Screen.Theme
+		targetField.model = lists.NewSelector(PriorityOptions(theme), theme.Priority(task.Priority).Name, messages.FormGoToWith)
+	case definitions.TaskDeadlineIndex:
+		if task.Deadline.IsZero() {
+			targetField.model = timepicker.New(time.Now())
+		} else {
+			targetField.model = timepicker.New(task.Deadline)
+		}
+	case definitions.TaskEstimateIndex:
+		estimate := ""
+		if task.Estimate > 0 {
+			estimate = durations.Format(task.Estimate)
+		}
+		targetField.model = text.New(estimate, "e.g. 1h30m", 20, messages.FormGoToWith)
+	case definitions.TaskRemindersIndex:
+		targetField.model = text.New(durations.FormatList(task.Reminders), "e.g. 1d, 1h", 40, messages.FormGoToWith)
+	}
+	return targetField
+}
+
+// setFocus will focus a field (of the full form)
+func (m *Form) setFocus(fieldIndex int) {
+	m.focusIndex = fieldIndex
+	m.helpKeys = m.fieldMap[fieldIndex].helpKeys
+	m.helpKeys.Next = keys.Mappings.Next
+	m.helpKeys.Previous = keys.Mappings.Previous
+}
+
+// PriorityOptions will get the selectable priorities (keyed by priority, valued by name)
+func PriorityOptions(theme display.Theme) []definitions.KeyValue {
+	var options []definitions.KeyValue
+	for priority := range uint64(entities.PriorityLevels) {
+		options = append(options, definitions.KeyValue{Key: fmt.Sprintf("%d", priority), Value: theme.Priority(priority).Name})
+	}
+	return options
+}
+
 // HelpKeys will get the help keys for the input form
 func (m Form) HelpKeys() keys.Map {
 	return m.helpKeys
@@ -179,17 +238,36 @@ func (m Form) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-	case messages.Form:
-		selectedValue := msg.Value
+		if m.isFull {
+			switch {
+			case key.Matches(msg, keys.Mappings.Next):
+				m.setFocus((m.focusIndex + 1) % len(m.fieldMap))
+				return m, m.fieldMap[m.focusIndex].model.Init()
+
+			case key.Matches(msg, keys.Mappings.Previous):
+				m.setFocus((m.focusIndex + len(m.fieldMap) - 1) % len(m.fieldMap))
+				return m, m.fieldMap[m.focusIndex].model.Init()
+
+			case key.Matches(msg, keys.Mappings.Save):
+				return m.saveAll()
+			}
+		}
 
-		if (m.fieldMap[m.focusIndex].isRequired) && (selectedValue == m.fieldMap[m.focusIndex].nilValue) {
-			m.isInvalid = true
-			m.invalidPrompt = m.fieldMap[m.focusIndex].validationPrompt
+	case messages.Form:
+		if m.isFull {
+			// fields (e.g. clearing the deadline) are only saved with the form
 			return m, nil
 		}
-		m.isInvalid = false
+		selectedValue := msg.Value
 
 		if m.isStacks {
+			if (m.fieldMap[m.focusIndex].isRequired) && (selectedValue == m.fieldMap[m.focusIndex].nilValue) {
+				m.isInvalid = true
+				m.invalidPrompt = m.fieldMap[m.focusIndex].validationPrompt
+				return m, nil
+			}
+			m.isInvalid = false
+
 			stack := m.data.(entities.Stack)
 
 			switch m.focusIndex {
@@ -200,17 +278,8 @@ func (m Form) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			stack.Save(m.context.DB)
 		} else {
 			task := m.data.(entities.Task)
-
-			switch m.focusIndex {
-			case definitions.TaskTitleIndex:
-				task.Title = selectedValue.(string)
-			case definitions.TaskNotesIndex:
-				task.Notes = selectedValue.(string)
-			case definitions.TaskPriorityIndex:
-				task.Priority, _ = strconv.ParseUint(selectedValue.(definitions.KeyValue).Value, 10, 64)
-			case definitions.TaskDeadlineIndex:
-				task.Deadline = selectedValue.(time.Time)
-
+			if !m.apply(&task, m.focusIndex, selectedValue) {
+				return m, nil
 			}
 
 			task = task.Save(m.context.DB).(entities.Task)
@@ -228,15 +297,104 @@ func (m Form) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// saveAll will validate every field (focusing the first invalid one) and save the task
+func (m Form) saveAll() (tea.Model, tea.Cmd) {
+	task := m.data.(entities.Task)
+	for fieldIndex := range len(m.fieldMap) {
+		if !m.apply(&task, fieldIndex, fieldValue(m.fieldMap[fieldIndex].model)) {
+			m.setFocus(fieldIndex)
+			return m, m.fieldMap[fieldIndex].model.Init()
+		}
+	}
+	task.Save(m.context.DB)
+	return m, messages.MainGoToWith("refresh")
+}
+
+// apply will validate and set a field value on the task, invalid values set the validation prompt
+func (m *Form) apply(task *entities.Task, fieldIndex int, value any) bool {
+	target := m.fieldMap[fieldIndex]
+	m.isInvalid = true
+	m.invalidIndex = fieldIndex
+	m.invalidPrompt = target.validationPrompt
+	if target.isRequired && value == target.nilValue {
+		return false
+	}
+
+	switch fieldIndex {
+	case definitions.TaskTitleIndex:
+		task.Title = value.(string)
+	case definitions.TaskNotesIndex:
+		task.Notes = value.(string)
+	case definitions.TaskPriorityIndex:
+		task.Priority, _ = strconv.ParseUint(value.(definitions.KeyValue).Key, 10, 64)
+	case definitions.TaskDeadlineIndex:
+		task.Deadline = value.(time.Time)
+	case definitions.TaskEstimateIndex:
+		task.Estimate = 0
+		if value := strings.TrimSpace(value.(string)); value != "" {
+			estimate, err := durations.Parse(value)
+			if err != nil || estimate < 0 {
+				return false
+			}
+			task.Estimate = estimate
+		}
+	case definitions.TaskRemindersIndex:
+		reminders, err := durations.ParseList(value.(string))
+		if err != nil {
+			return false
+		}
+		task.Reminders = reminders
+	}
+	m.isInvalid = false
+	return true
+}
+
+// fieldValue will get the current (unsaved) value of a field model
+func fieldValue(model tea.Model) any {
+	switch model := model.(type) {
+	case text.Input:
+		return model.Value()
+	case textarea.Input:
+		return model.Value()
+	case lists.Selector:
+		return model.Value()
+	case timepicker.Input:
+		return model.Value()
+	}
+	return nil
+}
+
+// summary will get a single line display of a field value (of the full form)
+func (m Form) summary(fieldIndex int) string {
+	var line string
+	switch value := fieldValue(m.fieldMap[fieldIndex].model).(type) {
+	case string:
+		line, _, _ = strings.Cut(value, "\n")
+	case definitions.KeyValue:
+		line = value.Value
+	case time.Time:
+		if !value.IsZero() {
+			line = timepicker.FormatTime(value, true)
+		}
+	}
+	if strings.TrimSpace(line) == "" {
+		return display.PlaceHolderStyle.Render("-")
+	}
+	return display.TextInputStyle.Render(line)
+}
+
 // View will display the model
 func (m Form) View() string {
+	if m.isFull {
+		return m.fullView()
+	}
 	var b strings.Builder
 
 	// NOTE: add changes for invalid input case
 	b.WriteString(display.HighlightedTextStyle.Render(m.fieldMap[m.focusIndex].prompt))
 
 	if m.isInvalid {
-		b.WriteString(lipgloss.NewStyle().Foreground(display.HighlightedBackgroundColor).Render("    **" + m.invalidPrompt))
+		b.WriteString(m.invalidView())
 	}
 
 	b.WriteRune('\n')
@@ -246,3 +404,32 @@ func (m Form) View() string {
 	b.WriteRune('\n')
 	return b.String()
 }
+
+func (m Form) invalidView() string {
+	return lipgloss.NewStyle().Foreground(display.HighlightedBackgroundColor).Render("    **" + m.invalidPrompt)
+}
+
+// fullView shows the focused field (to edit) and the values of the others
+func (m Form) fullView() string {
+	var b strings.Builder
+	for fieldIndex := range len(m.fieldMap) {
+		target := m.fieldMap[fieldIndex]
+		if fieldIndex == m.focusIndex {
+			b.WriteRune('\n')
+			b.WriteString(display.HighlightedTextStyle.Render(target.prompt))
+		} else {
+			b.WriteString(display.PlaceHolderStyle.Render(fmt.Sprintf("%-10s ", target.name)))
+			b.WriteString(m.summary(fieldIndex))
+		}
+		if m.isInvalid && fieldIndex == m.invalidIndex {
+			b.WriteString(m.invalidView())
+		}
+		b.WriteRune('\n')
+		if fieldIndex == m.focusIndex {
+			b.WriteRune('\n')
+			b.WriteString(target.model.View())
+			b.WriteString("\n\n")
+		}
+	}
+	return b.String()
+}
@@ -20,6 +20,9 @@ type mockDB struct{}
 func (m *mockDB) Add(string, any) {
 }
 
+func (m *mockDB) Nest(string, string, any) {
+}
+
 func (m *mockDB) AddChild(string, string, any) {
 }
 
@@ -40,6 +43,10 @@ func (m *mockDB) Errored() bool {
 func (m *mockDB) Log(_ string, _ error) {
 }
 
+func (m *mockDB) Batch(changes func()) {
+	changes()
+}
+
 func TestStackForm(t *testing.T) {
 	ctx := &state.Context{}
 	ctx.DB = &mockDB{}
@@ -93,6 +100,10 @@ func TestTaskForm(t *testing.T) {
 			send = definitions.KeyValue{}
 		case 3:
 			send = time.Now()
+		case 4:
+			send = "1h30m"
+		case 5:
+			send = "1d, 1h"
 		}
 
 		s := inputs.NewTaskForm(entities.Task{Deadline: now}, ofType, ctx)
@@ -106,7 +117,16 @@ func TestTaskForm(t *testing.T) {
 			t.Errorf("invalid form handle: %v", val)
 		}
 	}
-	s := inputs.NewTaskForm(entities.Task{}, 0, ctx)
+	s := inputs.NewTaskForm(entities.Task{}, definitions.TaskEstimateIndex, ctx)
+	_, m := s.Update(messages.Form{Value: "soon"})
+	if m != nil {
+		t.Error("invalid estimate should not save")
+	}
+	s = inputs.NewTaskForm(entities.Task{}, definitions.TaskRemindersIndex, ctx)
+	if _, m = s.Update(messages.Form{Value: "1d, later"}); m != nil {
+		t.Error("invalid reminders should not save")
+	}
+	s = inputs.NewTaskForm(entities.Task{}, 0, ctx)
 	s.HelpKeys()
 	if s.Init() != nil {
 		t.Error("invalid init")
@@ -115,7 +135,7 @@ func TestTaskForm(t *testing.T) {
 	if !strings.Contains(v, "Task Title ") {
 		t.Errorf("invalid view: %s", v)
 	}
-	_, m := s.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	_, m = s.Update(tea.KeyMsg{Type: tea.KeyEsc})
 	if _, ok := m().(messages.Main); !ok {
 		t.Error("invalid form")
 	}
@@ -128,3 +148,67 @@ func TestTaskForm(t *testing.T) {
 		t.Error("invalid result")
 	}
 }
+
+func TestFullTaskForm(t *testing.T) {
+	ctx := &state.Context{}
+	ctx.DB = &mockDB{}
+	ctx.Screen = &display.Screen{}
+	var m tea.Model = inputs.NewFullTaskForm(entities.Task{}, ctx)
+	if strings.Count(m.View(), "\n") <= strings.Count(inputs.NewTaskForm(entities.Task{}, 0, ctx).View(), "\n") {
+		t.Error("invalid full form")
+	}
+	if keys := m.(inputs.Form).HelpKeys(); len(keys.Next.Keys()) == 0 || len(keys.Previous.Keys()) == 0 {
+		t.Error("invalid help keys")
+	}
+	for _, text := range []string{"Task Title", "Notes", "Priority", "Deadline", "Estimate", "Reminders"} {
+		if !strings.Contains(m.View(), text) {
+			t.Errorf("missing %s: %s", text, m.View())
+		}
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("should refocus")
+	}
+	if _, ok := cmd().(messages.Main); ok || !strings.Contains(m.View(), "can not be empty") {
+		t.Errorf("title is required: %s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("abc")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if !strings.Contains(m.View(), "Task Estimate") {
+		t.Errorf("invalid focus: %s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("soon")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.View(), "Task Title") || !strings.Contains(m.View(), "soon") {
+		t.Errorf("invalid focus: %s", m.View())
+	}
+	if _, cmd := m.Update(messages.Form{Value: "xyz"}); cmd != nil {
+		t.Error("fields should only save with the form")
+	}
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if _, ok := cmd().(messages.Main); ok || !strings.Contains(m.View(), "Task Estimate") || !strings.Contains(m.View(), "must be a duration") {
+		t.Errorf("estimate is invalid: %s", m.View())
+	}
+	for range 4 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1h")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if msg, ok := cmd().(messages.Main); !ok || msg.Value != "refresh" {
+		t.Errorf("should save: %v", msg)
+	}
+}
+
+func TestPriorityOptions(t *testing.T) {
+	theme := display.DefaultTheme()
+	theme.Priorities = []display.PriorityLevel{{Name: "Someday"}, {}, {Name: "Normal"}}
+	options := inputs.PriorityOptions(theme)
+	if len(options) != entities.PriorityLevels {
+		t.Errorf("invalid options: %v", options)
+	}
+	if options[0].Key != "0" || options[0].Value != "Someday" || options[1].Value != "1" || options[4].Key != "4" || options[4].Value != "4" {
+		t.Errorf("invalid options: %v", options)
+	}
+}
@@ -59,6 +59,11 @@ func (m Input) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// Value will get the current (unsaved) value
+func (m Input) Value() string {
+	return m.input.Value()
+}
+
 // View will view the model
 func (m Input) View() string {
 	// Can't just render textinput.Value(), otherwise cursor blinking wouldn't work
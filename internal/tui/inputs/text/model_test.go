@@ -28,6 +28,10 @@ func TestInput(t *testing.T) {
 	if _, ok := c().(messages.Form); ok {
 		t.Error("invalid command")
 	}
+	obj, _ = obj.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ab")})
+	if obj.(text.Input).Value() != "ab" {
+		t.Errorf("invalid value: %s", obj.(text.Input).Value())
+	}
 	v := obj.View()
 	if !strings.Contains(v, ">") {
 		t.Errorf("invalid input: %s", v)
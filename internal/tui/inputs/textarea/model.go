@@ -55,6 +55,11 @@ func (m Input) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// Value will get the current (unsaved) value
+func (m Input) Value() string {
+	return m.input.Value()
+}
+
 // View will display the view
 func (m Input) View() string {
 	// Can't just render textarea.Value(), otherwise cursor blinking wouldn't work
@@ -29,6 +29,10 @@ func TestInput(t *testing.T) {
 	if _, ok := c().(messages.Form); ok {
 		t.Error("invalid command")
 	}
+	obj, _ = textarea.New("", &display.Screen{}).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ab")})
+	if obj.(textarea.Input).Value() != "ab" {
+		t.Errorf("invalid value: %s", obj.(textarea.Input).Value())
+	}
 	v := obj.View()
 	if !strings.Contains(v, "┃") {
 		t.Errorf("invalid input: %s", v)
@@ -19,6 +19,7 @@ type (
 	Input struct {
 		currTime   time.Time
 		focusIndex int
+		unset      bool
 	}
 
 	timeUnit struct {
@@ -73,6 +74,22 @@ func New(currTime time.Time) tea.Model {
 	return t
 }
 
+// NewUnset will create a new time picker without a time set (starting from the given time once changed)
+func NewUnset(currTime time.Time) tea.Model {
+	return Input{
+		currTime: currTime,
+		unset:    true,
+	}
+}
+
+// Value will get the current (unsaved) time, zero when not set
+func (m Input) Value() time.Time {
+	if m.unset {
+		return time.Time{}
+	}
+	return m.currTime
+}
+
 // Init will init the model
 func (m Input) Init() tea.Cmd {
 	return nil
@@ -85,6 +102,7 @@ func (m Input) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 
 		case key.Matches(msg, keys.Mappings.Up):
+			m.unset = false
 			switch m.focusIndex {
 			case hourItem:
 				hourDuration, _ := time.ParseDuration("60m")
@@ -102,6 +120,7 @@ func (m Input) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, keys.Mappings.Down):
+			m.unset = false
 			switch m.focusIndex {
 			case hourItem:
 				hourDuration, _ := time.ParseDuration("60m")
@@ -130,10 +149,10 @@ func (m Input) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case key.Matches(msg, keys.Mappings.Save):
-			return m, messages.FormGoToWith(m.currTime)
+			return m, messages.FormGoToWith(m.Value())
 		case key.Matches(msg, keys.Mappings.Delete):
-			m.currTime = time.Time{}
-			return m, messages.FormGoToWith(m.currTime)
+			m.unset = true
+			return m, messages.FormGoToWith(m.Value())
 		}
 	}
 	return m, nil
@@ -170,6 +189,13 @@ func (m Input) View() string {
 		" ",
 		renderMidDayInfo(m.currTime.Hour()))
 
+	if m.unset {
+		return lipgloss.JoinVertical(lipgloss.Center,
+			timeValue,
+			timeUnitLabel,
+			display.PlaceHolderStyle.Render("not set ('↑'/'↓' to set)"),
+		)
+	}
 	return lipgloss.JoinVertical(lipgloss.Center,
 		timeValue,
 		timeUnitLabel,
@@ -47,3 +47,27 @@ func TestInput(t *testing.T) {
 		t.Error("invalid results")
 	}
 }
+
+func TestUnset(t *testing.T) {
+	now := time.Now()
+	obj := timepicker.NewUnset(now)
+	if !obj.(timepicker.Input).Value().IsZero() || !strings.Contains(obj.View(), "not set") {
+		t.Error("should not be set")
+	}
+	_, cmd := obj.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if msg := cmd().(messages.Form); !msg.Value.(time.Time).IsZero() {
+		t.Errorf("invalid value: %v", msg)
+	}
+	obj, _ = obj.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if !obj.(timepicker.Input).Value().IsZero() {
+		t.Error("moving should not set")
+	}
+	obj, _ = obj.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if value := obj.(timepicker.Input).Value(); !value.Equal(now.AddDate(0, 1, 0)) || strings.Contains(obj.View(), "not set") {
+		t.Errorf("invalid value: %v", value)
+	}
+	obj, cmd = obj.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if msg := cmd().(messages.Form); !msg.Value.(time.Time).IsZero() || !obj.(timepicker.Input).Value().IsZero() {
+		t.Errorf("should clear: %v", msg)
+	}
+}
@@ -0,0 +1,213 @@
+package keys
+
+import "github.com/charmbracelet/bubbles/key"
+
+// Context is where an action is available
+type Context int
+
+const (
+	// StackContext is the stack table
+	StackContext Context = iota
+	// TaskContext is the task table
+	TaskContext
+	// DetailsContext is the task details
+	DetailsContext
+	// GlobalContext actions are available everywhere
+	GlobalContext
+	// NavigationContext is moving around the stack/task tables and details
+	NavigationContext
+	// FormContext is editing a field
+	FormContext
+	// TimePickerContext is editing a date/time field
+	TimePickerContext
+	// ListContext is picking from a list
+	ListContext
+	// TrashContext is the trash view
+	TrashContext
+	// ArchiveContext is the archive view
+	ArchiveContext
+	// PaletteContext is the command palette
+	PaletteContext
+	// StatsContext is the stats screen
+	StatsContext
+	// FocusContext is the focus view (the tasks planned for today)
+	FocusContext
+)
+
+type (
+	// Action is something that can be done by its binding or via the command palette, the registry of
+	// actions (Actions) drives both the palette and the help of each context
+	Action struct {
+		Name        string
+		Binding     key.Binding
+		Context     Context
+		Description string
+		// Args describes the argument (if any), an argument picks the option the action would prompt for
+		Args string
+	}
+
+	// Bindings are key bindings (shown as help)
+	Bindings []key.Binding
+
+	// Group is a titled context (of the help screen)
+	Group struct {
+		Title   string
+		Context Context
+	}
+)
+
+// Groups are the contexts shown in the help screen (in order)
+var Groups = []Group{
+	{Title: "stacks", Context: StackContext},
+	{Title: "tasks", Context: TaskContext},
+	{Title: "details", Context: DetailsContext},
+	{Title: "navigation", Context: NavigationContext},
+	{Title: "everywhere", Context: GlobalContext},
+	{Title: "forms", Context: FormContext},
+	{Title: "time picker", Context: TimePickerContext},
+	{Title: "lists", Context: ListContext},
+	{Title: "trash", Context: TrashContext},
+	{Title: "archive", Context: ArchiveContext},
+	{Title: "command palette", Context: PaletteContext},
+	{Title: "stats", Context: StatsContext},
+	{Title: "focus", Context: FocusContext},
+}
+
+// Actions are all actions (in the order they are shown)
+var Actions = []Action{
+	{Name: "new", Binding: Mappings.New, Context: StackContext, Description: "add a stack"},
+	{Name: "edit", Binding: Mappings.Edit, Context: StackContext, Description: "rename the stack"},
+	{Name: "delete", Binding: Mappings.Delete, Context: StackContext, Description: "move the stack (and its tasks) to the trash"},
+	{Name: "move", Binding: Mappings.Move, Context: StackContext, Description: "nest the stack under another stack (or the top level)", Args: "<stack>"},
+	{Name: "trash", Binding: Mappings.Trash, Context: StackContext, Description: "view the trash to restore/purge"},
+	{Name: "archive", Binding: Mappings.Archive, Context: StackContext, Description: "archive the stack (and its tasks)"},
+	{Name: "archives", Binding: Mappings.Archives, Context: StackContext, Description: "view the archive to unarchive"},
+	{Name: "move-up", Binding: Mappings.MoveUp, Context: StackContext, Description: "move the stack up"},
+	{Name: "move-down", Binding: Mappings.MoveDown, Context: StackContext, Description: "move the stack down"},
+	{Name: "collapse", Binding: Mappings.Collapse, Context: StackContext, Description: "collapse/expand the nested stacks"},
+	{Name: "profile", Binding: Mappings.Profile, Context: StackContext, Description: "switch to another profile", Args: "<profile>"},
+	{Name: "template", Binding: Mappings.Template, Context: StackContext, Description: "create a stack (and its tasks) from a template", Args: "<template>"},
+	{Name: "save-template", Binding: Mappings.SaveAs, Context: StackContext, Description: "save the stack (and its tasks) as a template"},
+	{Name: "clone", Binding: Mappings.Clone, Context: StackContext, Description: "copy the stack and its tasks (resetting finished tasks/shifting deadlines)"},
+
+	{Name: "toggle", Binding: Mappings.Toggle, Context: TaskContext, Description: "finish/unfinish the marked (or selected) tasks"},
+	{Name: "plan", Binding: Mappings.Plan, Context: TaskContext, Description: "plan the marked (or selected) tasks for today (or unplan them)"},
+	{Name: "new", Binding: Mappings.New, Context: TaskContext, Description: "add a task"},
+	{Name: "edit", Binding: Mappings.Edit, Context: TaskContext, Description: "edit the task"},
+	{Name: "delete", Binding: Mappings.Delete, Context: TaskContext, Description: "move the marked (or selected) tasks to the trash"},
+	{Name: "clone", Binding: Mappings.Clone, Context: TaskContext, Description: "copy the marked (or selected) tasks (unfinished) into a stack", Args: "<stack>"},
+	{Name: "move", Binding: Mappings.Move, Context: TaskContext, Description: "move the task to another stack", Args: "<stack>"},
+	{Name: "filter", Binding: Mappings.Filters, Context: TaskContext, Description: "show/hide finished tasks (see display.finished.since)"},
+	{Name: "mark", Binding: Mappings.Mark, Context: TaskContext, Description: "mark/unmark the task"},
+	{Name: "mark-all", Binding: Mappings.MarkAll, Context: TaskContext, Description: "mark all tasks"},
+	{Name: "invert", Binding: Mappings.Invert, Context: TaskContext, Description: "invert the marks"},
+	{Name: "priority", Binding: Mappings.Priority, Context: TaskContext, Description: "set the priority of the marked (or selected) tasks", Args: "<priority>"},
+	{Name: "shift", Binding: Mappings.Shift, Context: TaskContext, Description: "shift the deadline of the marked (or selected) tasks", Args: "<offset>"},
+	{Name: "trash", Binding: Mappings.Trash, Context: TaskContext, Description: "view the trash to restore/purge"},
+	{Name: "archive", Binding: Mappings.Archive, Context: TaskContext, Description: "archive the marked (or selected) tasks"},
+	{Name: "archives", Binding: Mappings.Archives, Context: TaskContext, Description: "view the archive to unarchive"},
+	{Name: "timer", Binding: Mappings.Timer, Context: TaskContext, Description: "start/stop tracking time on the task"},
+	{Name: "sort", Binding: Mappings.Sort, Context: TaskContext, Description: "cycle how the stack's tasks are sorted"},
+	{Name: "move-up", Binding: Mappings.MoveUp, Context: TaskContext, Description: "move the task up (sorting the stack manually)"},
+	{Name: "move-down", Binding: Mappings.MoveDown, Context: TaskContext, Description: "move the task down (sorting the stack manually)"},
+	{Name: "template", Binding: Mappings.Template, Context: TaskContext, Description: "add the tasks of a template to the stack", Args: "<template>"},
+	{Name: "save-template", Binding: Mappings.SaveAs, Context: TaskContext, Description: "save the marked (or selected) tasks as a template"},
+
+	{Name: "edit", Binding: Mappings.Edit, Context: DetailsContext, Description: "edit the focused field"},
+
+	{Name: "up", Binding: Mappings.Up, Context: NavigationContext, Description: "move up (or to the previous details field)"},
+	{Name: "down", Binding: Mappings.Down, Context: NavigationContext, Description: "move down (or to the next details field)"},
+	{Name: "left", Binding: Mappings.Left, Context: NavigationContext, Description: "focus the pane to the left"},
+	{Name: "right", Binding: Mappings.Right, Context: NavigationContext, Description: "focus the pane to the right (showing the tasks/details)"},
+	{Name: "commands", Binding: Mappings.Command, Context: NavigationContext, Description: "open the command palette"},
+
+	{Name: "help", Binding: Mappings.Help, Context: GlobalContext, Description: "show/hide this help"},
+	{Name: "hints", Binding: Mappings.Hints, Context: GlobalContext, Description: "show/hide the key hints (below the panes)"},
+	{Name: "stats", Binding: Mappings.Stats, Context: GlobalContext, Description: "show the stats (tasks finished over time, open/finished/overdue tasks per stack)"},
+	{Name: "focus", Binding: Mappings.Focus, Context: GlobalContext, Description: "focus on the tasks planned for today"},
+	{Name: "quit", Binding: Mappings.Quit, Context: GlobalContext, Description: "quit"},
+
+	{Name: "save", Binding: Mappings.Save, Context: FormContext, Description: "save the field (or every field of a new task)"},
+	{Name: "next", Binding: Mappings.Next, Context: FormContext, Description: "next field (of a new task)"},
+	{Name: "previous", Binding: Mappings.Previous, Context: FormContext, Description: "previous field (of a new task)"},
+	{Name: "return", Binding: Mappings.Return, Context: FormContext, Description: "return without saving"},
+	{Name: "new-line", Binding: Mappings.NewLine, Context: FormContext, Description: "start a new line (in notes)"},
+	{Name: "exit", Binding: Mappings.Exit, Context: FormContext, Description: "quit (without saving)"},
+
+	{Name: "up", Binding: Mappings.Up, Context: TimePickerContext, Description: "increase the focused part of the date/time"},
+	{Name: "down", Binding: Mappings.Down, Context: TimePickerContext, Description: "decrease the focused part of the date/time"},
+	{Name: "left", Binding: Mappings.Left, Context: TimePickerContext, Description: "focus the previous part of the date/time"},
+	{Name: "right", Binding: Mappings.Right, Context: TimePickerContext, Description: "focus the next part of the date/time"},
+	{Name: "delete", Binding: Mappings.Delete, Context: TimePickerContext, Description: "clear the date/time"},
+	{Name: "save", Binding: Mappings.Save, Context: TimePickerContext, Description: "save the date/time"},
+
+	{Name: "up", Binding: Mappings.Up, Context: ListContext, Description: "previous option"},
+	{Name: "down", Binding: Mappings.Down, Context: ListContext, Description: "next option"},
+	{Name: "save", Binding: Mappings.Save, Context: ListContext, Description: "pick the option"},
+	{Name: "return", Binding: Mappings.Return, Context: ListContext, Description: "return without picking"},
+
+	{Name: "restore", Binding: Mappings.Restore, Context: TrashContext, Description: "restore the stack/task (picking a stack when its stack is gone)"},
+	{Name: "purge", Binding: Mappings.Purge, Context: TrashContext, Description: "permanently delete the stack/task"},
+	{Name: "return", Binding: Mappings.Return, Context: TrashContext, Description: "return to the stacks/tasks"},
+
+	{Name: "unarchive", Binding: Mappings.Unarchive, Context: ArchiveContext, Description: "move the stack/task back out of the archive"},
+	{Name: "return", Binding: Mappings.Return, Context: ArchiveContext, Description: "return to the stacks/tasks"},
+
+	{Name: "up", Binding: PaletteMappings.Up, Context: PaletteContext, Description: "previous command"},
+	{Name: "down", Binding: PaletteMappings.Down, Context: PaletteContext, Description: "next command"},
+	{Name: "run", Binding: Mappings.Run, Context: PaletteContext, Description: "run the command (an argument picks from the list it would show)"},
+	{Name: "return", Binding: Mappings.Return, Context: PaletteContext, Description: "return without running"},
+
+	{Name: "window", Binding: Mappings.Next, Context: StatsContext, Description: "next window (last 7/30/90 days, all time)"},
+	{Name: "return", Binding: Mappings.Return, Context: StatsContext, Description: "return to the stacks/tasks"},
+
+	{Name: "up", Binding: Mappings.Up, Context: FocusContext, Description: "previous task"},
+	{Name: "down", Binding: Mappings.Down, Context: FocusContext, Description: "next task"},
+	{Name: "toggle", Binding: Mappings.Toggle, Context: FocusContext, Description: "finish/unfinish the task"},
+	{Name: "plan", Binding: Mappings.Plan, Context: FocusContext, Description: "take the task off today"},
+	{Name: "return", Binding: Mappings.Return, Context: FocusContext, Description: "return to the stacks/tasks"},
+}
+
+// For will get the actions available in a context (including the global actions)
+func For(context Context) []Action {
+	var actions []Action
+	for _, action := range Actions {
+		if action.Context == context || action.Context == GlobalContext {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// Find will get an action (by name) available in a context
+func Find(context Context, name string) (Action, bool) {
+	for _, action := range For(context) {
+		if action.Name == name {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// Help will get the bindings of the actions of a context (not including the global actions)
+func Help(context Context) Bindings {
+	var bindings Bindings
+	for _, action := range Actions {
+		if action.Context == context {
+			bindings = append(bindings, action.Binding)
+		}
+	}
+	return bindings
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (b Bindings) ShortHelp() []key.Binding {
+	return b
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (b Bindings) FullHelp() [][]key.Binding {
+	return [][]key.Binding{b}
+}
@@ -5,22 +5,50 @@ import "github.com/charmbracelet/bubbles/key"
 
 // Map is the key binding map definition
 type Map struct {
-	Up      key.Binding
-	Down    key.Binding
-	Left    key.Binding
-	Right   key.Binding
-	New     key.Binding
-	Edit    key.Binding
-	Move    key.Binding
-	Save    key.Binding
-	NewLine key.Binding
-	Toggle  key.Binding
-	Delete  key.Binding
-	Return  key.Binding
-	Help    key.Binding
-	Quit    key.Binding
-	Exit    key.Binding
-	Filters key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	New       key.Binding
+	Edit      key.Binding
+	Move      key.Binding
+	Save      key.Binding
+	NewLine   key.Binding
+	Toggle    key.Binding
+	Delete    key.Binding
+	Return    key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+	Exit      key.Binding
+	Filters   key.Binding
+	Mark      key.Binding
+	MarkAll   key.Binding
+	Invert    key.Binding
+	Priority  key.Binding
+	Shift     key.Binding
+	Trash     key.Binding
+	Restore   key.Binding
+	Purge     key.Binding
+	Archive   key.Binding
+	Archives  key.Binding
+	Unarchive key.Binding
+	Timer     key.Binding
+	Sort      key.Binding
+	MoveUp    key.Binding
+	MoveDown  key.Binding
+	Collapse  key.Binding
+	Profile   key.Binding
+	Command   key.Binding
+	Run       key.Binding
+	Hints     key.Binding
+	Next      key.Binding
+	Previous  key.Binding
+	Template  key.Binding
+	SaveAs    key.Binding
+	Clone     key.Binding
+	Stats     key.Binding
+	Plan      key.Binding
+	Focus     key.Binding
 }
 
 var (
@@ -72,7 +100,7 @@ var (
 		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
-			key.WithHelp("'?'", "toggle help"),
+			key.WithHelp("'?'", "help"),
 		),
 		Quit: key.NewBinding(
 			key.WithKeys("q"),
@@ -90,6 +118,118 @@ var (
 			key.WithKeys("ctrl+s"),
 			key.WithHelp("'ctrl+s'", "save"),
 		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("'space'", "mark"),
+		),
+		MarkAll: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("'a'", "mark all"),
+		),
+		Invert: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("'i'", "invert marks"),
+		),
+		Priority: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("'p'", "priority"),
+		),
+		Shift: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("'s'", "shift deadline"),
+		),
+		Trash: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("'t'", "trash"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("'r'", "restore"),
+		),
+		Purge: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("'x'", "purge"),
+		),
+		Archive: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("'A'", "archive"),
+		),
+		Archives: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("'v'", "view archive"),
+		),
+		Unarchive: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("'u'", "unarchive"),
+		),
+		Timer: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("'T'", "start/stop timer"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("'o'", "cycle sort"),
+		),
+		MoveUp: key.NewBinding(
+			key.WithKeys("K", "shift+up"),
+			key.WithHelp("'K'", "move up"),
+		),
+		MoveDown: key.NewBinding(
+			key.WithKeys("J", "shift+down"),
+			key.WithHelp("'J'", "move down"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("'c'", "collapse/expand"),
+		),
+		Profile: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("'P'", "switch profile"),
+		),
+		Command: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp("':'", "commands"),
+		),
+		Run: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("'enter'", "run"),
+		),
+		Hints: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("'H'", "toggle hints"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("'tab'", "next field"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("'shift+tab'", "previous field"),
+		),
+		Template: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("'N'", "new from template"),
+		),
+		SaveAs: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("'S'", "save as template"),
+		),
+		Clone: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("'C'", "clone"),
+		),
+		Stats: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("'R'", "stats"),
+		),
+		Plan: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("'d'", "plan today"),
+		),
+		Focus: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("'F'", "focus"),
+		),
 	}
 
 	// TextInputMappings are for form text fields
@@ -122,41 +262,67 @@ var (
 	}
 	// DetailsMappings handle moving through the details screen
 	DetailsMappings = Map{
-		Up:   Mappings.Up,
-		Down: Mappings.Down,
-		Help: Mappings.Help,
-		Quit: Mappings.Quit,
+		Up:      Mappings.Up,
+		Down:    Mappings.Down,
+		Help:    Mappings.Help,
+		Quit:    Mappings.Quit,
+		Command: Mappings.Command,
+		Hints:   Mappings.Hints,
+		Stats:   Mappings.Stats,
+		Focus:   Mappings.Focus,
 	}
 	// TaskDetailsMappings manage editing a task
-	TaskDetailsMappings = Map{
-		Edit: Mappings.Edit,
+	TaskDetailsMappings = Help(DetailsContext)
+	// PaletteMappings are for the command palette
+	PaletteMappings = Map{
+		Up:     key.NewBinding(key.WithKeys("up"), key.WithHelp("'↑'", "up")),
+		Down:   key.NewBinding(key.WithKeys("down"), key.WithHelp("'↓'", "down")),
+		Run:    Mappings.Run,
+		Return: Mappings.Return,
 	}
 
-	// StackMappings navigate the stack
-	StackMappings = Map{
-		New:    Mappings.New,
-		Edit:   Mappings.Edit,
-		Delete: Mappings.Delete,
+	// TrashMappings handle restoring/purging from the trash
+	TrashMappings = Map{
+		Up:      Mappings.Up,
+		Down:    Mappings.Down,
+		Restore: Mappings.Restore,
+		Purge:   Mappings.Purge,
+		Return:  Mappings.Return,
+	}
+	// FocusMappings handle the tasks planned for today
+	FocusMappings = Map{
+		Up:     Mappings.Up,
+		Down:   Mappings.Down,
+		Toggle: Mappings.Toggle,
+		Plan:   Mappings.Plan,
+		Return: Mappings.Return,
+	}
+	// ArchiveMappings handle browsing the archive
+	ArchiveMappings = Map{
+		Up:        Mappings.Up,
+		Down:      Mappings.Down,
+		Unarchive: Mappings.Unarchive,
+		Return:    Mappings.Return,
 	}
 
+	// StackMappings navigate the stack
+	StackMappings = Help(StackContext)
+
 	// TaskMappings navigate the tasks
-	TaskMappings = Map{
-		Toggle:  Mappings.Toggle,
-		New:     Mappings.New,
-		Edit:    Mappings.Edit,
-		Delete:  Mappings.Delete,
-		Move:    Mappings.Move,
-		Filters: Mappings.Filters,
-	}
+	TaskMappings = Help(TaskContext)
 
 	// TableMappings navigate a table
 	TableMappings = Map{
-		Up:    Mappings.Up,
-		Down:  Mappings.Down,
-		Left:  Mappings.Left,
-		Right: Mappings.Right,
-		Help:  Mappings.Help,
-		Quit:  Mappings.Quit,
+		Up:      Mappings.Up,
+		Down:    Mappings.Down,
+		Left:    Mappings.Left,
+		Right:   Mappings.Right,
+		Help:    Mappings.Help,
+		Quit:    Mappings.Quit,
+		Command: Mappings.Command,
+		Hints:   Mappings.Hints,
+		Stats:   Mappings.Stats,
+		Focus:   Mappings.Focus,
 	}
 )
 
@@ -179,6 +345,34 @@ func (k Map) ShortHelp() []key.Binding {
 		k.Help,
 		k.Quit,
 		k.Filters,
+		k.Mark,
+		k.MarkAll,
+		k.Invert,
+		k.Priority,
+		k.Shift,
+		k.Trash,
+		k.Restore,
+		k.Purge,
+		k.Archive,
+		k.Archives,
+		k.Unarchive,
+		k.Timer,
+		k.Sort,
+		k.MoveUp,
+		k.MoveDown,
+		k.Collapse,
+		k.Profile,
+		k.Run,
+		k.Command,
+		k.Hints,
+		k.Next,
+		k.Previous,
+		k.Template,
+		k.SaveAs,
+		k.Clone,
+		k.Stats,
+		k.Plan,
+		k.Focus,
 	}
 }
 
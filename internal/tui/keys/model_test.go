@@ -19,3 +19,35 @@ func TestFullHelp(t *testing.T) {
 		t.Error("invalid full help")
 	}
 }
+
+func TestActions(t *testing.T) {
+	seen := make(map[keys.Context]map[string]bool)
+	for _, action := range keys.Actions {
+		if seen[action.Context] == nil {
+			seen[action.Context] = make(map[string]bool)
+		}
+		if seen[action.Context][action.Name] || action.Description == "" || len(action.Binding.Keys()) == 0 {
+			t.Errorf("invalid action: %v", action)
+		}
+		seen[action.Context][action.Name] = true
+	}
+	for _, context := range []keys.Context{keys.StackContext, keys.TaskContext, keys.DetailsContext} {
+		if len(keys.For(context)) != len(keys.Help(context))+len(keys.Help(keys.GlobalContext)) {
+			t.Errorf("global actions should be available: %d", context)
+		}
+	}
+	if action, ok := keys.Find(keys.TaskContext, "move"); !ok || action.Args == "" {
+		t.Errorf("invalid action: %v", action)
+	}
+	if _, ok := keys.Find(keys.DetailsContext, "move"); ok {
+		t.Error("move is not a details action")
+	}
+	for _, group := range keys.Groups {
+		if group.Title == "" || len(keys.Help(group.Context)) == 0 {
+			t.Errorf("invalid help group: %v", group)
+		}
+	}
+	if len(keys.StackMappings.ShortHelp()) == 0 || len(keys.TaskMappings.FullHelp()[0]) == 0 {
+		t.Error("invalid help")
+	}
+}
@@ -0,0 +1,148 @@
+// Package palette is the command palette (listing and running actions)
+package palette
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+// maxItems is how many actions are listed at once
+const maxItems = 8
+
+type (
+	// Model is the command palette
+	Model struct {
+		actions    []keys.Action
+		matches    []keys.Action
+		input      textinput.Model
+		focusIndex int
+	}
+
+	// Command is the action to run (sent to the main view, cancelling sends an empty message)
+	Command struct {
+		Name string
+		Args string
+	}
+)
+
+// New will create a command palette for actions
+func New(actions []keys.Action) tea.Model {
+	t := textinput.New()
+	t.Prompt = ":"
+	t.Cursor.Style = display.TextInputStyle
+	t.PromptStyle = display.TextInputStyle
+	t.TextStyle = display.TextInputStyle
+	t.Placeholder = "command [argument]"
+	t.Focus()
+	m := Model{actions: actions, input: t}
+	m.filter()
+	return m
+}
+
+// Parse will split input into the command (name) and its arguments
+func Parse(input string) (string, string) {
+	name, args, _ := strings.Cut(strings.TrimSpace(input), " ")
+	return name, strings.TrimSpace(args)
+}
+
+// filter will list the actions matching the command (best first)
+func (m *Model) filter() {
+	name, _ := Parse(m.input.Value())
+	type scored struct {
+		action keys.Action
+		score  int
+	}
+	var results []scored
+	for _, action := range m.actions {
+		score := definitions.Fuzzy(name, action.Name)
+		if score >= 0 {
+			// a match on the name is preferred over the description
+			score *= 2
+		}
+		score = max(score, definitions.Fuzzy(name, action.Description))
+		if score >= 0 {
+			results = append(results, scored{action, score})
+		}
+	}
+	slices.SortStableFunc(results, func(x, y scored) int {
+		return y.score - x.score
+	})
+	m.matches = nil
+	for _, result := range results {
+		m.matches = append(m.matches, result.action)
+	}
+	m.focusIndex = 0
+}
+
+// Init will init the model
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update will update the model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.Mappings.Return):
+			return m, messages.MainGoTo
+		case key.Matches(msg, keys.Mappings.Exit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Mappings.Run, keys.Mappings.Save):
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			_, args := Parse(m.input.Value())
+			return m, messages.MainGoToWith(Command{Name: m.matches[m.focusIndex].Name, Args: args})
+		case key.Matches(msg, keys.PaletteMappings.Up):
+			if m.focusIndex > 0 {
+				m.focusIndex--
+			}
+			return m, nil
+		case key.Matches(msg, keys.PaletteMappings.Down):
+			if m.focusIndex < len(m.matches)-1 {
+				m.focusIndex++
+			}
+			return m, nil
+		}
+	}
+	// only a change to the command (not the arguments) changes the matches
+	name, _ := Parse(m.input.Value())
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if updated, _ := Parse(m.input.Value()); updated != name {
+		m.filter()
+	}
+	return m, cmd
+}
+
+// View will show the model
+func (m Model) View() string {
+	lines := []string{m.input.View(), ""}
+	if len(m.matches) == 0 {
+		lines = append(lines, display.PlaceHolderStyle.Render("no matching commands"))
+	}
+	start, end := display.ListWindow(len(m.matches), m.focusIndex, maxItems)
+	for idx := start; idx < end; idx++ {
+		action := m.matches[idx]
+		prefix := "  "
+		style := lipgloss.NewStyle().Foreground(display.InputFormColor)
+		if idx == m.focusIndex {
+			prefix = "» "
+			style = style.Bold(true)
+		}
+		name := strings.TrimSpace(action.Name + " " + action.Args)
+		line := fmt.Sprintf("%s%-22s %-8s %s", prefix, name, action.Binding.Help().Key, action.Description)
+		lines = append(lines, style.Render(line))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
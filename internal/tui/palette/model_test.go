@@ -0,0 +1,73 @@
+package palette_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+	"github.com/enckse/mayhem/internal/tui/palette"
+)
+
+func typed(m tea.Model, value string) tea.Model {
+	for _, r := range value {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	name, args := palette.Parse("  move  Work stuff ")
+	if name != "move" || args != "Work stuff" {
+		t.Errorf("invalid parse: %s, %s", name, args)
+	}
+}
+
+func TestPalette(t *testing.T) {
+	m := palette.New(keys.For(keys.TaskContext))
+	if m.Init() == nil {
+		t.Error("invalid init")
+	}
+	if v := m.View(); !strings.Contains(v, "» toggle") || !strings.Contains(v, "'tab'") {
+		t.Errorf("invalid view: %s", v)
+	}
+	m = typed(m, "prio 3")
+	if v := m.View(); !strings.Contains(v, "» priority <priority>") {
+		t.Errorf("invalid view: %s", v)
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(messages.Main)
+	if !ok || msg.Value != (palette.Command{Name: "priority", Args: "3"}) {
+		t.Errorf("invalid command: %v", msg)
+	}
+	m = typed(palette.New(keys.For(keys.TaskContext)), "zzz")
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !strings.Contains(m.View(), "no matching commands") {
+		t.Error("nothing should match")
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if msg, ok := cmd().(messages.Main); !ok || msg.Value != "" {
+		t.Errorf("invalid cancel: %v", msg)
+	}
+}
+
+func TestPaletteNavigation(t *testing.T) {
+	m := palette.New(keys.For(keys.DetailsContext))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if !strings.Contains(m.View(), "» quit") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if !strings.Contains(m.View(), "» focus") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+}
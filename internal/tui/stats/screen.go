@@ -0,0 +1,102 @@
+// Package stats is the full screen stats (throughput, open/finished tasks per stack)
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/reports"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+// screenChrome is the title and footer lines around the (scrolling) stats
+const screenChrome = 4
+
+// Window is a span the stats cover (all time when zero)
+type Window struct {
+	Title string
+	Span  time.Duration
+}
+
+// Windows are the spans the stats screen cycles through
+var Windows = []Window{
+	{Title: "last 7 days", Span: 7 * 24 * time.Hour},
+	{Title: "last 30 days", Span: 30 * 24 * time.Hour},
+	{Title: "last 90 days", Span: 90 * 24 * time.Hour},
+	{Title: "all time"},
+}
+
+// Screen is the full screen stats of the stacks
+type Screen struct {
+	viewport viewport.Model
+	stacks   []entities.Stack
+	window   int
+}
+
+// NewScreen will create the stats screen (over the last 30 days)
+func NewScreen(stacks []entities.Stack, width, height int) Screen {
+	m := Screen{viewport: viewport.New(width, max(height-screenChrome, 1)), stacks: stacks, window: 1}
+	m.viewport.SetContent(Content(stacks, Windows[m.window], time.Now()))
+	return m
+}
+
+// Content will render the stats of the stacks within a window until a time
+func Content(stacks []entities.Stack, window Window, until time.Time) string {
+	var since time.Time
+	if window.Span > 0 {
+		since = until.Add(-window.Span)
+	}
+	var b strings.Builder
+	reports.Stats(stacks, since, until).Write(&b)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Init is the model init
+func (m Screen) Init() tea.Cmd {
+	return nil
+}
+
+// Update will update the model
+func (m Screen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-screenChrome, 1)
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Mappings.Next):
+			m.window = (m.window + 1) % len(Windows)
+			m.viewport.SetContent(Content(m.stacks, Windows[m.window], time.Now()))
+			m.viewport.GotoTop()
+			return m, nil
+		case key.Matches(msg, keys.Mappings.Stats), key.Matches(msg, keys.Mappings.Return):
+			return m, messages.MainGoTo
+		case key.Matches(msg, keys.Mappings.Exit):
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View will display the model
+func (m Screen) View() string {
+	footer := fmt.Sprintf("%3.f%% · %s window · '↑'/'↓'/'pgup'/'pgdown' scroll · %s/%s close", m.viewport.ScrollPercent()*100, keys.Mappings.Next.Help().Key, keys.Mappings.Stats.Help().Key, keys.Mappings.Return.Help().Key)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		display.HighlightedTextStyle.Render(fmt.Sprintf("Stats (%s)", Windows[m.window].Title)),
+		"",
+		m.viewport.View(),
+		"",
+		display.PlaceHolderStyle.Render(footer),
+	)
+}
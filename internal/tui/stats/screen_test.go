@@ -0,0 +1,52 @@
+package stats_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/tui/messages"
+	"github.com/enckse/mayhem/internal/tui/stats"
+)
+
+func TestScreen(t *testing.T) {
+	now := time.Now()
+	old := entities.Task{Title: "old", Finished: now.Add(-60 * 24 * time.Hour)}
+	recent := entities.Task{Title: "recent", Finished: now.Add(-time.Hour)}
+	stacks := []entities.Stack{{Title: "Work", Tasks: []entities.Task{old, recent, {Title: "open"}}}}
+	content := stats.Content(stacks, stats.Windows[1], now)
+	for _, text := range []string{"finished 1 · open 1", "finished per day", "Work"} {
+		if !strings.Contains(content, text) {
+			t.Errorf("missing %s: %s", text, content)
+		}
+	}
+	if content := stats.Content(stacks, stats.Window{}, now); !strings.Contains(content, "finished 2 · open 1") || !strings.Contains(content, "from the beginning") {
+		t.Errorf("invalid all time content: %s", content)
+	}
+	var m tea.Model = stats.NewScreen(stacks, 100, 30)
+	if m.Init() != nil {
+		t.Error("invalid init")
+	}
+	if !strings.Contains(m.View(), "Stats (last 30 days)") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.View(), "Stats (all time)") || !strings.Contains(m.View(), "finished 2") {
+		t.Errorf("invalid window: %s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.View(), "Stats (last 7 days)") {
+		t.Errorf("windows should cycle: %s", m.View())
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if _, ok := cmd().(messages.Main); !ok {
+		t.Error("should close")
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if _, ok := cmd().(messages.Main); !ok {
+		t.Error("should close")
+	}
+}
@@ -3,9 +3,11 @@ package tables
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/display"
 	"github.com/enckse/mayhem/internal/entities"
 	"github.com/enckse/mayhem/internal/tui/inputs/timepicker"
@@ -14,39 +16,50 @@ import (
 var (
 	// StackColumns are the table columns for stacks
 	StackColumns = []table.Column{
-		{Title: "       Stacks", Width: 20},
+		{Title: "       Stacks", Width: 16},
 		{Title: "", Width: 5},
+		{Title: "", Width: 2},
 	}
 	// TaskColumns are the table columns for tasks
 	TaskColumns = []table.Column{
-		{Title: "", Width: 1},
-		{Title: "           Tasks", Width: 30},
+		{Title: "", Width: 3},
+		{Title: "           Tasks", Width: 28},
 		{Title: "     Deadline", Width: 20},
 		{Title: "Priority", Width: 8},
 	}
 )
 
-// StackRows will generate rows for stack
-func StackRows(stacks []entities.Stack) []table.Row {
-	rows := make([]table.Row, len(stacks))
-
-	entities.SortStacks(stacks)
-
-	for i, val := range stacks {
+// StackRows will generate rows for stacks as a tree (with open and overdue task counts, including nested stacks),
+// getting the stacks in row order (stacks nested under collapsed stacks are not shown)
+func StackRows(stacks []entities.Stack, collapsed map[string]bool, now time.Time) ([]table.Row, []entities.Stack) {
+	tree := entities.NewStackTree(stacks)
+	shown := tree.Ordered(collapsed)
+	rows := make([]table.Row, len(shown))
+
+	for i, val := range shown {
+		var marker string
+		if tree.HasChildren(val.ID) {
+			marker = "▾ "
+			if collapsed[val.ID] {
+				marker = "▸ "
+			}
+		}
 		row := []string{
-			val.Title,
-			formatCount(val.OpenTasks()),
+			strings.Repeat("  ", tree.Depth(val.ID)) + marker + val.Title,
+			formatCount(tree.OpenTasks(val)),
+			formatOverdue(tree.OverdueTasks(val, now)),
 		}
 		rows[i] = row
 	}
-	return rows
+	return rows, shown
 }
 
-// TaskRows will generate rows for tasks
-func TaskRows(tasks []entities.Task, since time.Time) []table.Row {
+// TaskRows will generate rows for tasks (sorted by strategy, marked tasks and tasks planned for today are flagged) and the style of each row (by deadline, then priority)
+func TaskRows(tasks []entities.Task, strategy entities.SortStrategy, since time.Time, marked map[string]bool, theme display.Theme, now time.Time) ([]table.Row, []lipgloss.Style) {
 	var rows []table.Row
+	var styles []lipgloss.Style
 
-	entities.SortTasks(tasks)
+	entities.SortTasksBy(tasks, strategy)
 
 	var prefix string
 	var deadline string
@@ -61,21 +74,40 @@ func TaskRows(tasks []entities.Task, since time.Time) []table.Row {
 				}
 			}
 			prefix = "✘"
+		} else if val.PlannedOn(now) {
+			prefix = "★"
 		} else {
 			prefix = "▢"
 		}
+		if marked[val.ID] {
+			prefix = "*" + prefix
+		}
+		priority := theme.Priority(val.Priority)
+		style := priority.Style
+		switch val.Due(now, theme.DueSoon) {
+		case entities.Overdue:
+			prefix += "!"
+			style = theme.Overdue
+		case entities.DueToday:
+			prefix += "●"
+			style = theme.DueToday
+		case entities.DueSoon:
+			prefix += "○"
+			style = theme.Upcoming
+		}
 
 		row := []string{
 			prefix,
 			val.Title,
 			deadline,
-			fmt.Sprintf("   %d", val.Priority),
+			center(priority.Name, TaskColumns[3].Width),
 		}
 
 		rows = append(rows, row)
+		styles = append(styles, style)
 	}
 
-	return rows
+	return rows, styles
 }
 
 // New will generate a new table model
@@ -92,6 +124,25 @@ func New(columns []table.Column, tableType display.TableType, screen *display.Sc
 	return t
 }
 
+func center(value string, width int) string {
+	pad := (width - lipgloss.Width(value)) / 2
+	if pad <= 0 {
+		return value
+	}
+	return strings.Repeat(" ", pad) + value
+}
+
+func formatOverdue(count uint64) string {
+	switch {
+	case count == 0:
+		return ""
+	case count < 10:
+		return fmt.Sprintf("!%d", count)
+	default:
+		return "!+"
+	}
+}
+
 func formatCount(count uint64) string {
 	switch {
 	case count == 0:
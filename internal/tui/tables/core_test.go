@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/display"
 	"github.com/enckse/mayhem/internal/entities"
 	"github.com/enckse/mayhem/internal/tui/tables"
@@ -21,24 +22,95 @@ func TestStackRows(t *testing.T) {
 		thousandTasks = append(thousandTasks, entities.Task{})
 		idx++
 	}
-	s := tables.StackRows([]entities.Stack{{Tasks: thousandTasks}, {Tasks: tenTasks}, {Title: "empty"}})
-	if fmt.Sprintf("%v", s) != "[[ [99+]] [ [ 10]] [empty      ]]" {
+	now := time.Now()
+	tenTasks[0].Deadline = now.Add(-time.Hour)
+	tenTasks[1].Deadline = now.Add(-time.Hour)
+	tenTasks[2].Deadline = now.Add(time.Hour)
+	s, shown := tables.StackRows([]entities.Stack{{Tasks: thousandTasks}, {Tasks: tenTasks}, {Title: "empty"}}, nil, now)
+	if fmt.Sprintf("%v", s) != "[[ [99+] ] [ [ 10] !2] [empty       ]]" {
 		t.Errorf("bad rows: %v", s)
 	}
+	if len(shown) != 3 {
+		t.Errorf("bad stacks: %v", shown)
+	}
+}
+
+func TestStackRowsNested(t *testing.T) {
+	now := time.Now()
+	stacks := []entities.Stack{
+		{ID: "c", Title: "child", ParentID: "p", Tasks: []entities.Task{{}, {Deadline: now.Add(-time.Hour)}}},
+		{ID: "g", Title: "grand", ParentID: "c", Tasks: []entities.Task{{}}},
+		{ID: "p", Title: "parent", Tasks: []entities.Task{{}}},
+		{ID: "o", Title: "other"},
+	}
+	s, shown := tables.StackRows(stacks, nil, now)
+	if fmt.Sprintf("%v", s) != "[[other       ] [▾ parent [  4] !1] [  ▾ child [  3] !1] [    grand [  1] ]]" {
+		t.Errorf("bad rows: %v", s)
+	}
+	if len(shown) != 4 || shown[2].ID != "c" {
+		t.Errorf("bad stacks: %v", shown)
+	}
+	s, shown = tables.StackRows(stacks, map[string]bool{"c": true}, now)
+	if fmt.Sprintf("%v", s) != "[[other       ] [▾ parent [  4] !1] [  ▸ child [  3] !1]]" {
+		t.Errorf("bad rows: %v", s)
+	}
+	if len(shown) != 3 {
+		t.Errorf("bad stacks: %v", shown)
+	}
 }
 
 func TestTaskRows(t *testing.T) {
 	tasks := []entities.Task{{Title: "xyz", Finished: time.Now()}, {Finished: time.Time{}}}
 	tasks[0].ID = "0"
 	tasks[1].ID = "1"
-	s := tables.TaskRows(tasks, time.Time{})
+	theme := display.DefaultTheme()
+	now := time.Now()
+	s, _ := tables.TaskRows(tasks, entities.SortDefault, time.Time{}, nil, theme, now)
 	if fmt.Sprintf("%v", s) != "[[▢           -    0] [✘ xyz          -    0]]" {
 		t.Errorf("bad rows: %v", s)
 	}
-	s = tables.TaskRows(tasks, time.Now())
+	s, _ = tables.TaskRows(tasks, entities.SortDefault, time.Now(), nil, theme, now)
 	if fmt.Sprintf("%v", s) != "[[▢           -    0]]" {
 		t.Errorf("bad rows: %v", s)
 	}
+	s, _ = tables.TaskRows(tasks, entities.SortDefault, time.Time{}, map[string]bool{"0": true}, theme, now)
+	if fmt.Sprintf("%v", s) != "[[▢           -    0] [*✘ xyz          -    0]]" {
+		t.Errorf("bad rows: %v", s)
+	}
+	for idx := range tasks {
+		if tasks[idx].ID == "1" {
+			tasks[idx].Planned = now
+		}
+	}
+	s, _ = tables.TaskRows(tasks, entities.SortDefault, time.Time{}, map[string]bool{"1": true}, theme, now)
+	if fmt.Sprintf("%v", s) != "[[*★           -    0] [✘ xyz          -    0]]" {
+		t.Errorf("bad rows: %v", s)
+	}
+}
+
+func TestTaskRowsDeadlines(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	tasks := []entities.Task{
+		{ID: "overdue", Title: "a", Deadline: now.Add(-time.Minute)},
+		{ID: "today", Title: "b", Deadline: now.Add(time.Hour)},
+		{ID: "soon", Title: "c", Deadline: now.Add(48 * time.Hour)},
+		{ID: "later", Title: "d", Deadline: now.Add(96 * time.Hour)},
+		{ID: "finished", Title: "e", Deadline: now.Add(-time.Hour), Finished: now},
+	}
+	theme := display.DefaultTheme()
+	rows, styles := tables.TaskRows(tasks, entities.SortDefault, time.Time{}, nil, theme, now)
+	var prefixes []string
+	for _, row := range rows {
+		prefixes = append(prefixes, row[0])
+	}
+	if strings.Join(prefixes, ",") != "▢!,▢●,▢○,▢,✘" {
+		t.Errorf("bad markers: %v", prefixes)
+	}
+	for idx, expect := range []lipgloss.Style{theme.Overdue, theme.DueToday, theme.Upcoming, lipgloss.NewStyle(), lipgloss.NewStyle()} {
+		if styles[idx].GetForeground() != expect.GetForeground() {
+			t.Errorf("bad style: %d", idx)
+		}
+	}
 }
 
 func TestNew(t *testing.T) {
@@ -48,3 +120,63 @@ func TestNew(t *testing.T) {
 		t.Errorf("invalid model: %v", res)
 	}
 }
+
+func TestViewport(t *testing.T) {
+	var v tables.Viewport
+	for _, check := range []struct {
+		total, cursor, height, start, end int
+	}{
+		{0, 0, 5, 0, 0},
+		{3, 2, 5, 0, 3},
+		{10, 4, 5, 0, 5},
+		{10, 6, 5, 2, 7},
+		{10, 5, 5, 2, 7},
+		{10, 1, 5, 1, 6},
+		{10, 9, 5, 5, 10},
+		{4, 3, 5, 0, 4},
+	} {
+		start, end := v.Range(check.total, check.cursor, check.height)
+		if start != check.start || end != check.end || v.Start() != start {
+			t.Errorf("invalid range: %v (%d, %d)", check, start, end)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := tables.New(tables.TaskColumns, display.TaskTableType, &display.Screen{})
+	m.SetHeight(5)
+	rows, styles := tables.TaskRows([]entities.Task{{ID: "1", Title: "a very long task title that will not fit"}, {ID: "2", Title: "b"}}, entities.SortDefault, time.Time{}, nil, display.DefaultTheme(), time.Now())
+	m.SetRows(rows)
+	var v tables.Viewport
+	view := tables.Render(m, display.TableStyle(display.TaskTableType), &v, func(row, _ int) lipgloss.Style {
+		return styles[row]
+	})
+	lines := strings.Split(view, "\n")
+	if len(lines) != 5 || lipgloss.Height(m.View()) != len(lines) {
+		t.Errorf("invalid height: %d", len(lines))
+	}
+	if !strings.Contains(view, "a very long task title that…") || !strings.Contains(view, "Tasks") {
+		t.Errorf("invalid render: %s", view)
+	}
+	if lipgloss.Width(view) != lipgloss.Width(m.View()) {
+		t.Errorf("invalid width: %d != %d", lipgloss.Width(view), lipgloss.Width(m.View()))
+	}
+}
+
+func TestTaskRowsPriorities(t *testing.T) {
+	theme := display.DefaultTheme()
+	theme.Priorities = []display.PriorityLevel{display.NewPriorityLevel("Someday", ""), display.NewPriorityLevel("", ""), display.NewPriorityLevel("Urgent", "9")}
+	now := time.Now()
+	tasks := []entities.Task{{ID: "1", Title: "a", Priority: 2}, {ID: "2", Title: "b", Priority: 1}, {ID: "3", Title: "c"}, {ID: "4", Title: "d", Priority: 2, Deadline: now.Add(-time.Hour)}}
+	rows, styles := tables.TaskRows(tasks, entities.SortDefault, time.Time{}, nil, theme, now)
+	var priorities []string
+	for _, row := range rows {
+		priorities = append(priorities, row[3])
+	}
+	if strings.Join(priorities, ",") != " Urgent, Urgent,   1,Someday" {
+		t.Errorf("invalid priorities: %q", priorities)
+	}
+	if styles[0].GetForeground() != theme.Overdue.GetForeground() || styles[1].GetForeground() != lipgloss.Color("9") {
+		t.Errorf("invalid styles: %v", styles)
+	}
+}
@@ -0,0 +1,96 @@
+package tables
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type (
+	// Viewport tracks the visible rows of a table, only scrolling as far as needed to show the cursor
+	Viewport struct {
+		start int
+	}
+
+	// CellStyle gets the style for a (non-selected) table cell
+	CellStyle func(row, col int) lipgloss.Style
+)
+
+// Range will get the visible rows [start, end) for the table
+func (v *Viewport) Range(total, cursor, height int) (int, int) {
+	if height <= 0 || total == 0 {
+		v.start = 0
+		return 0, 0
+	}
+	if cursor < v.start {
+		v.start = cursor
+	}
+	if cursor >= v.start+height {
+		v.start = cursor - height + 1
+	}
+	v.start = max(min(v.start, total-height), 0)
+	return v.start, min(v.start+height, total)
+}
+
+// Start is the first visible row (as of the last render)
+func (v Viewport) Start() int {
+	return v.start
+}
+
+// Render will render a table (as table.Model.View does) but allow styling individual cells
+func Render(t table.Model, styles table.Styles, viewport *Viewport, style CellStyle) string {
+	var headers []string
+	for _, col := range t.Columns() {
+		if col.Width <= 0 {
+			continue
+		}
+		headers = append(headers, styles.Header.Render(cell(col.Title, col.Width, lipgloss.NewStyle())))
+	}
+	rows := t.Rows()
+	start, end := viewport.Range(len(rows), t.Cursor(), t.Height())
+	var lines []string
+	for r := start; r < end; r++ {
+		selected := r == t.Cursor()
+		var cells []string
+		for c, value := range rows[r] {
+			width := t.Columns()[c].Width
+			if width <= 0 {
+				continue
+			}
+			cellStyle := lipgloss.NewStyle()
+			if style != nil && !selected {
+				cellStyle = style(r, c)
+			}
+			cells = append(cells, styles.Cell.Render(cell(value, width, cellStyle)))
+		}
+		line := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+		if selected {
+			line = styles.Selected.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	body := lipgloss.NewStyle().Height(t.Height()).MaxHeight(t.Height()).Render(strings.Join(lines, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, headers...) + "\n" + body
+}
+
+func cell(value string, width int, style lipgloss.Style) string {
+	return style.Width(width).MaxWidth(width).Inline(true).Render(truncate(value, width))
+}
+
+func truncate(value string, width int) string {
+	if lipgloss.Width(value) <= width {
+		return value
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range value {
+		size := lipgloss.Width(string(r))
+		if used+size > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += size
+	}
+	return b.String() + "…"
+}
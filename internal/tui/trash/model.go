@@ -0,0 +1,230 @@
+// Package trash handles restoring and purging deleted stacks/tasks
+package trash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/inputs/lists"
+	"github.com/enckse/mayhem/internal/tui/inputs/timepicker"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+const (
+	isConfirm = "y"
+	// maxItems is how many trash entries are rendered at once
+	maxItems = 10
+)
+
+type (
+	// Model is the trash view
+	Model struct {
+		context    *state.Context
+		trash      entities.Trash
+		items      []item
+		focusIndex int
+		changed    bool
+		confirm    bool
+		// selector picks a new stack when restoring a task whose stack is gone
+		selector tea.Model
+	}
+
+	item struct {
+		isStack bool
+		stack   entities.Stack
+		task    entities.Task
+	}
+
+	restoreTarget definitions.KeyValue
+)
+
+// New will create a new trash view
+func New(ctx *state.Context) tea.Model {
+	m := Model{context: ctx}
+	m.load()
+	return m
+}
+
+func (m *Model) load() {
+	m.trash = entities.FetchTrash(m.context.DB)
+	m.items = []item{}
+	for _, s := range m.trash.Stacks {
+		m.items = append(m.items, item{isStack: true, stack: s})
+	}
+	for _, t := range m.trash.Tasks {
+		m.items = append(m.items, item{task: t})
+	}
+	if m.focusIndex >= len(m.items) {
+		m.focusIndex = len(m.items) - 1
+	}
+	if m.focusIndex < 0 {
+		m.focusIndex = 0
+	}
+}
+
+// Init will init the model
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) exit() tea.Cmd {
+	if m.changed {
+		return messages.MainGoToWith("refresh")
+	}
+	return messages.MainGoTo
+}
+
+// Update will update the model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case restoreTarget:
+		m.selector = nil
+		if msg.Key == "" {
+			return m, nil
+		}
+		task := m.items[m.focusIndex].task
+		task.StackID = msg.Key
+		task.Restore(m.context.DB)
+		m.changed = true
+		m.load()
+		return m, nil
+	case tea.KeyMsg:
+		if m.selector != nil {
+			if key.Matches(msg, keys.Mappings.Return) {
+				m.selector = nil
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.selector, cmd = m.selector.Update(msg)
+			return m, cmd
+		}
+		if m.confirm {
+			m.confirm = false
+			if strings.ToLower(msg.String()) == isConfirm {
+				selected := m.items[m.focusIndex]
+				if selected.isStack {
+					selected.stack.Purge(m.context.DB)
+				} else {
+					selected.task.Purge(m.context.DB)
+				}
+				m.changed = true
+				m.load()
+			}
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, keys.Mappings.Return):
+			return m, m.exit()
+		case key.Matches(msg, keys.Mappings.Exit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Mappings.Up):
+			if m.focusIndex > 0 {
+				m.focusIndex--
+			}
+		case key.Matches(msg, keys.Mappings.Down):
+			if m.focusIndex < len(m.items)-1 {
+				m.focusIndex++
+			}
+		case key.Matches(msg, keys.Mappings.Purge):
+			if len(m.items) > 0 {
+				m.confirm = true
+			}
+		case key.Matches(msg, keys.Mappings.Restore):
+			if len(m.items) == 0 {
+				return m, nil
+			}
+			selected := m.items[m.focusIndex]
+			if selected.isStack {
+				selected.stack.Restore(m.context.DB)
+			} else {
+				if m.trash.Orphaned(selected.task) {
+					m.openSelector()
+					return m, nil
+				}
+				selected.task.Restore(m.context.DB)
+			}
+			m.changed = true
+			m.load()
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) openSelector() {
+	var opts []definitions.KeyValue
+	for _, s := range m.trash.Available {
+		opts = append(opts, definitions.KeyValue{Key: s.ID, Value: s.Title})
+	}
+	if len(opts) == 0 {
+		m.context.DB.Log("trash", fmt.Errorf("no stack available to restore task: %s", m.items[m.focusIndex].task.Title))
+		return
+	}
+	m.selector = lists.NewSelector(opts, "", func(value any) tea.Cmd {
+		return func() tea.Msg {
+			return restoreTarget(value.(definitions.KeyValue))
+		}
+	})
+}
+
+// HelpKeys will get the help keys for the current trash state
+func (m Model) HelpKeys() keys.Map {
+	if m.selector != nil {
+		return keys.ListSelectorMappings
+	}
+	return keys.TrashMappings
+}
+
+// View will display the model
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(display.HighlightedTextStyle.Render("Trash"))
+	b.WriteString("\n\n")
+	if len(m.items) == 0 {
+		b.WriteString(display.PlaceHolderStyle.Render("Trash is empty"))
+		return b.String()
+	}
+	selected := m.items[m.focusIndex]
+	switch {
+	case m.selector != nil:
+		b.WriteString(display.TextInputStyle.Render(fmt.Sprintf("Original stack is gone, restore '%s' to:", selected.task.Title)))
+		b.WriteString("\n\n")
+		b.WriteString(m.selector.View())
+		return b.String()
+	case m.confirm:
+		return lipgloss.NewStyle().Foreground(display.HighlightedBackgroundColor).Padding(1, 0).Render(fmt.Sprintf("Permanently delete '%s'? (%s/n): ", selected.title(), isConfirm))
+	}
+	var res []string
+	start, end := display.ListWindow(len(m.items), m.focusIndex, maxItems)
+	for i := start; i < end; i++ {
+		entry := m.items[i]
+		prefix := "  "
+		if i == m.focusIndex {
+			prefix = "» "
+		}
+		res = append(res, lipgloss.NewStyle().Foreground(display.InputFormColor).Bold(true).Render(prefix+entry.describe(m.trash)))
+	}
+	b.WriteString(lipgloss.JoinVertical(lipgloss.Left, res...))
+	return b.String()
+}
+
+func (i item) title() string {
+	if i.isStack {
+		return i.stack.Title
+	}
+	return i.task.Title
+}
+
+func (i item) describe(trash entities.Trash) string {
+	if i.isStack {
+		return fmt.Sprintf("[stack] %-30s %3d task(s)  deleted %s", i.stack.Title, len(i.stack.Tasks), timepicker.FormatTime(i.stack.Deleted, true))
+	}
+	return fmt.Sprintf("[task]  %-30s %-12s deleted %s", i.task.Title, trash.StackTitle(i.task.StackID), timepicker.FormatTime(i.task.Deleted, true))
+}
@@ -0,0 +1,103 @@
+package trash_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/backend"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/messages"
+	"github.com/enckse/mayhem/internal/tui/trash"
+)
+
+func newContext() (*state.Context, entities.Stack, entities.Stack) {
+	var buf bytes.Buffer
+	ctx := &state.Context{}
+	ctx.DB = backend.NewMemoryBased("", false, &buf)
+	live := entities.Stack{ID: "live", Title: "live"}
+	live.Save(ctx.DB)
+	dead := entities.Stack{ID: "dead", Title: "dead"}
+	dead.Save(ctx.DB)
+	task := entities.Task{ID: "task", Title: "task", StackID: "dead"}
+	task.Save(ctx.DB)
+	task.Delete(ctx.DB)
+	dead.Delete(ctx.DB)
+	return ctx, live, dead
+}
+
+func update(m tea.Model, msgs ...tea.Msg) (tea.Model, tea.Msg) {
+	var last tea.Msg
+	for _, msg := range msgs {
+		var cmd tea.Cmd
+		m, cmd = m.Update(msg)
+		for cmd != nil {
+			last = cmd()
+			if _, ok := last.(messages.Main); ok {
+				break
+			}
+			m, cmd = m.Update(last)
+		}
+	}
+	return m, last
+}
+
+func TestView(t *testing.T) {
+	ctx, _, _ := newContext()
+	m := trash.New(ctx)
+	if m.Init() != nil {
+		t.Error("invalid init")
+	}
+	v := m.View()
+	if !strings.Contains(v, "[stack] dead") || !strings.Contains(v, "[task]  task") {
+		t.Errorf("invalid view: %s", v)
+	}
+	_, msg := update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if val, ok := msg.(messages.Main); !ok || val.Value != "" {
+		t.Errorf("invalid exit: %v", msg)
+	}
+}
+
+func TestRestoreOrphan(t *testing.T) {
+	ctx, _, _ := newContext()
+	m := trash.New(ctx)
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if !strings.Contains(m.View(), "Original stack is gone") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	stacks := entities.FetchStacks(ctx.DB)
+	if len(stacks) != 1 || len(stacks[0].Tasks) != 1 || stacks[0].Tasks[0].StackID != "live" {
+		t.Errorf("task not restored: %v", stacks)
+	}
+	_, msg := update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if val, ok := msg.(messages.Main); !ok || val.Value != "refresh" {
+		t.Errorf("invalid exit: %v", msg)
+	}
+}
+
+func TestRestorePurge(t *testing.T) {
+	ctx, _, _ := newContext()
+	m := trash.New(ctx)
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if len(entities.FetchStacks(ctx.DB)) != 2 {
+		t.Error("stack not restored")
+	}
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !strings.Contains(m.View(), "Permanently delete 'task'") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if entities.FetchTrash(ctx.DB).Empty() {
+		t.Error("should not purge")
+	}
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if !entities.FetchTrash(ctx.DB).Empty() {
+		t.Error("should purge")
+	}
+	if !strings.Contains(m.View(), "Trash is empty") {
+		t.Errorf("invalid view: %s", m.View())
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/help"
+)
+
+// shiftOptions are the offsets a (bulk) deadline shift can apply, keyed by duration
+var shiftOptions = []definitions.KeyValue{
+	{Key: "-168h", Value: "-1 week"},
+	{Key: "-24h", Value: "-1 day"},
+	{Key: "1h", Value: "+1 hour"},
+	{Key: "24h", Value: "+1 day"},
+	{Key: "168h", Value: "+1 week"},
+}
+
+// Marks are task IDs within the currently selected stack
+func (m *model) toggleMark(taskIndex int) {
+	id := m.data[m.stackTable.Cursor()].Tasks[taskIndex].ID
+	if m.marked[id] {
+		delete(m.marked, id)
+	} else {
+		m.marked[id] = true
+	}
+}
+
+func (m *model) clearMarks() {
+	m.marked = make(map[string]bool)
+}
+
+// selectedTasks are the marked tasks or, when nothing is marked, the task under the cursor
+func (m *model) selectedTasks() []entities.Task {
+	tasks := m.data[m.stackTable.Cursor()].Tasks
+	if len(m.marked) == 0 {
+		if len(tasks) == 0 {
+			return nil
+		}
+		return []entities.Task{tasks[m.taskTable.Cursor()]}
+	}
+	var selected []entities.Task
+	for _, t := range tasks {
+		if m.marked[t.ID] {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
+
+// bulkUpdate will apply a change to all selected tasks as a single store write
+func (m *model) bulkUpdate(change func(entities.Task)) {
+	selected := m.selectedTasks()
+	m.context.DB.Batch(func() {
+		for _, t := range selected {
+			change(t)
+		}
+	})
+	m.clearMarks()
+}
+
+func (m *model) showBulkInput(inputType string, input tea.Model, helpModel help.Model) {
+	m.preInputFocus = taskViewName
+	m.showCustomInput = true
+	m.customInputType = inputType
+	m.customInput = input
+	m.taskTable.Blur()
+	m.help = helpModel
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/help"
+	"github.com/enckse/mayhem/internal/tui/inputs/lists"
+	"github.com/enckse/mayhem/internal/tui/inputs/text"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+// cloneFinishedOptions are how finished tasks are cloned (with a stack)
+var cloneFinishedOptions = []definitions.KeyValue{
+	{Key: "keep", Value: "keep finished tasks finished"},
+	{Key: "reset", Value: "reset finished tasks"},
+}
+
+// showCloneTasks will list the stacks to clone the selected tasks into (the current stack first selected)
+func (m *model) showCloneTasks() tea.Cmd {
+	opts := m.stackOptions(nil)
+	var current string
+	for _, opt := range opts {
+		if opt.Key == m.data[m.stackTable.Cursor()].ID {
+			current = opt.Value
+		}
+	}
+	m.showBulkInput(definitions.IsClone, lists.NewSelector(opts, current, messages.MainGoToWith), help.NewModel(keys.ListSelectorMappings))
+	return nil
+}
+
+// cloneTasks will clone the selected tasks into a stack
+func (m *model) cloneTasks(stackID string) {
+	entities.CloneTasks(m.context.DB, m.selectedTasks(), stackID)
+	m.clearMarks()
+	m.preserveState()
+	m.refreshData()
+}
+
+// showCloneStack will start cloning the selected stack (asking how finished tasks are cloned)
+func (m *model) showCloneStack() tea.Cmd {
+	m.cloneOptions = entities.CloneOptions{}
+	return m.showStackInput(definitions.IsCloneStack, lists.NewSelector(cloneFinishedOptions, "", messages.MainGoToWith), help.NewModel(keys.ListSelectorMappings))
+}
+
+// showCloneShift will ask how deadlines are shifted when cloning the selected stack (e.g. +1w, -2d)
+func (m *model) showCloneShift() tea.Cmd {
+	input := text.New("", "shift deadlines by (e.g. +1w, -2d), empty keeps them", 20, messages.MainGoToWith)
+	return m.showStackInput(definitions.IsCloneShift, input, help.NewModel(keys.TextInputMappings))
+}
+
+// cloneStack will clone the selected stack, shifting deadlines by an offset (selecting the clone)
+func (m *model) cloneStack(shift time.Duration) {
+	m.cloneOptions.Shift = shift
+	stack := entities.CloneStack(m.context.DB, m.data[m.stackTable.Cursor()], m.cloneOptions)
+	m.preserveState()
+	m.prevState.stackID = stack.ID
+	m.prevState.taskID = ""
+	m.taskTable.SetCursor(0)
+	m.refreshData()
+}
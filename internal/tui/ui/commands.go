@@ -0,0 +1,89 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/help"
+	"github.com/enckse/mayhem/internal/tui/inputs/lists"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+	"github.com/enckse/mayhem/internal/tui/palette"
+)
+
+// focusContext is the (action) context of the focused pane
+func (m *model) focusContext() (keys.Context, string, bool) {
+	switch {
+	case m.stackTable.Focused():
+		return keys.StackContext, stackViewName, true
+	case m.taskTable.Focused():
+		return keys.TaskContext, taskViewName, true
+	case m.taskDetails.Focused():
+		return keys.DetailsContext, detailViewName, true
+	}
+	return 0, "", false
+}
+
+// showPalette will show the command palette for the actions of the focused pane
+func (m *model) showPalette() tea.Cmd {
+	context, view, ok := m.focusContext()
+	if !ok {
+		return nil
+	}
+	m.preInputFocus = view
+	m.showCustomInput = true
+	m.customInputType = definitions.IsCommand
+	m.customInput = palette.New(keys.For(context))
+	m.stackTable.Blur()
+	m.taskTable.Blur()
+	m.taskDetails.Blur()
+	m.help = help.NewModel(keys.PaletteMappings)
+	return m.customInput.Init()
+}
+
+// runCommand will run an action (as its binding would) in the focused pane, an argument
+// picks the matching option when the action prompts with a list (e.g. ':move Work')
+func (m *model) runCommand(command palette.Command) (tea.Model, tea.Cmd) {
+	context, _, ok := m.focusContext()
+	if !ok {
+		return m, nil
+	}
+	action, ok := keys.Find(context, command.Name)
+	if !ok {
+		return m, nil
+	}
+	msg, ok := keyMsg(action.Binding.Keys()[0])
+	if !ok {
+		return m, nil
+	}
+	_, cmd := m.Update(msg)
+	if command.Args == "" || !m.showCustomInput {
+		return m, cmd
+	}
+	selector, ok := m.customInput.(lists.Selector)
+	if !ok {
+		return m, cmd
+	}
+	// without a match the list is left open to pick from
+	option, ok := selector.Match(command.Args)
+	if !ok {
+		return m, cmd
+	}
+	_, selected := m.Update(messages.Main{Value: option})
+	return m, tea.Batch(cmd, selected)
+}
+
+// keyMsg will get the key message for a key (as named by bindings)
+func keyMsg(name string) (tea.KeyMsg, bool) {
+	if runes := []rune(name); len(runes) == 1 {
+		if name == " " {
+			return tea.KeyMsg{Type: tea.KeySpace, Runes: runes}, true
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: runes}, true
+	}
+	for keyType := tea.KeyType(-128); keyType < 128; keyType++ {
+		if keyType != tea.KeyRunes && keyType.String() == name {
+			return tea.KeyMsg{Type: keyType}, true
+		}
+	}
+	return tea.KeyMsg{}, false
+}
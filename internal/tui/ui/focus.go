@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/focus"
+	"github.com/enckse/mayhem/internal/tui/help"
+	"github.com/enckse/mayhem/internal/tui/inputs/lists"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+// showFocus will show the focus view (the tasks planned for today)
+func (m *model) showFocus() {
+	m.screen = focus.New(m.context, m.context.Screen.Width, m.context.Screen.Height)
+	m.showScreen = true
+}
+
+// planTasks will plan the selected tasks for today, unplanning them when all are already planned for today
+func (m *model) planTasks() {
+	now := time.Now()
+	tasks := m.selectedTasks()
+	day := time.Time{}
+	for _, task := range tasks {
+		if !task.PlannedOn(now) {
+			day = now
+		}
+	}
+	entities.PlanTasks(m.context.DB, tasks, day)
+	m.clearMarks()
+	m.preserveState()
+	m.refreshData()
+}
+
+// watchDay will tick at the start of the next day (to offer carrying over planned tasks)
+func (m *model) watchDay() tea.Cmd {
+	now := time.Now()
+	return m.dayTickIn(entities.Day(now).AddDate(0, 0, 1).Sub(now))
+}
+
+func (m *model) dayTickIn(wait time.Duration) tea.Cmd {
+	generation := m.tickGeneration
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return dayTick(generation)
+	})
+}
+
+// checkRollOver will offer (once a day) to carry over unfinished tasks planned before today
+func (m *model) checkRollOver() {
+	today := entities.Day(time.Now())
+	if m.rolledOver.Equal(today) {
+		return
+	}
+	m.rolledOver = today
+	stale := entities.RollOverTasks(m.stacks, today)
+	if len(stale) == 0 {
+		return
+	}
+	opts := []definitions.KeyValue{
+		{Key: "carry", Value: fmt.Sprintf("carry over %d unfinished task(s) planned before today", len(stale))},
+		{Key: "unplan", Value: fmt.Sprintf("unplan the %d task(s)", len(stale))},
+	}
+	m.preInputFocus = stackViewName
+	switch {
+	case m.taskTable.Focused():
+		m.preInputFocus = taskViewName
+	case m.taskDetails.Focused():
+		m.preInputFocus = detailViewName
+	}
+	m.showCustomInput = true
+	m.customInputType = definitions.IsRollOver
+	m.customInput = lists.NewSelector(opts, "", messages.MainGoToWith)
+	m.stackTable.Blur()
+	m.taskTable.Blur()
+	m.taskDetails.Blur()
+	m.help = help.NewModel(keys.ListSelectorMappings)
+}
+
+// rollOver will carry over (or unplan) the unfinished tasks planned before today
+func (m *model) rollOver(option string) {
+	now := time.Now()
+	day := now
+	if option != "carry" {
+		day = time.Time{}
+	}
+	entities.PlanTasks(m.context.DB, entities.RollOverTasks(m.stacks, now), day)
+	m.preserveState()
+	m.refreshData()
+}
+
+// closeScreen will return from a (help/stats/focus) screen, refreshing when the screen changed anything
+func (m *model) closeScreen(value any) {
+	m.showScreen = false
+	if value == "refresh" {
+		m.preserveState()
+		m.refreshData()
+	}
+}
@@ -3,6 +3,8 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -10,8 +12,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/enckse/mayhem/internal/display"
+	"github.com/enckse/mayhem/internal/durations"
 	"github.com/enckse/mayhem/internal/entities"
 	"github.com/enckse/mayhem/internal/state"
+	"github.com/enckse/mayhem/internal/tui/archive"
 	"github.com/enckse/mayhem/internal/tui/definitions"
 	"github.com/enckse/mayhem/internal/tui/deletion"
 	"github.com/enckse/mayhem/internal/tui/details"
@@ -20,7 +24,10 @@ import (
 	"github.com/enckse/mayhem/internal/tui/inputs/lists"
 	"github.com/enckse/mayhem/internal/tui/keys"
 	"github.com/enckse/mayhem/internal/tui/messages"
+	"github.com/enckse/mayhem/internal/tui/palette"
+	"github.com/enckse/mayhem/internal/tui/stats"
 	"github.com/enckse/mayhem/internal/tui/tables"
+	"github.com/enckse/mayhem/internal/tui/trash"
 )
 
 type (
@@ -30,7 +37,9 @@ type (
 	}
 
 	model struct {
-		data            []entities.Stack
+		data            []entities.Stack // the visible stacks (in tree order)
+		stacks          []entities.Stack // all stacks (including nested stacks under collapsed stacks)
+		collapsed       map[string]bool
 		stackTable      table.Model
 		taskTable       table.Model
 		taskDetails     details.Box
@@ -40,6 +49,8 @@ type (
 		showDetails     bool
 		showInput       bool
 		showHelp        bool
+		screen          tea.Model // full screen (help/stats) shown instead of the panes
+		showScreen      bool
 		customInput     tea.Model
 		customInputType string
 		showCustomInput bool
@@ -50,6 +61,16 @@ type (
 		context         *state.Context
 		filterSince     time.Duration
 		canFilter       bool
+		marked          map[string]bool
+		taskStyles      []lipgloss.Style
+		stackViewport   tables.Viewport
+		taskViewport    tables.Viewport
+		session         state.Session // restored on first render
+		cloneOptions    entities.CloneOptions
+		rolledOver      time.Time // the day unfinished planned tasks were last offered to carry over
+		ticking         bool      // whether a timer tick is scheduled
+		tickGeneration  int       // ticks of another generation (e.g. a previous profile) are dropped
+		notice          string    // shown below the tables until the next key press
 	}
 
 	preserveState struct {
@@ -58,6 +79,10 @@ type (
 		taskID      string
 	}
 	dataCategory int
+	// timerTick refreshes the running timer indicator (tagged with the generation that scheduled it)
+	timerTick int
+	// dayTick is the start of a new day (tagged with the generation that scheduled it)
+	dayTick int
 )
 
 const (
@@ -66,6 +91,9 @@ const (
 	taskViewName   = "task"
 )
 
+// tablesOffset is the height taken around the tables (footers, status and help)
+const tablesOffset = 10
+
 const (
 	stackDataCategory dataCategory = iota
 	taskDataCategory
@@ -81,12 +109,15 @@ func Initialize(ctx *state.Context) ModelWrapper {
 		taskTable:  tables.New(tables.TaskColumns, display.TaskTableType, ctx.Screen),
 		// we can't build the details box at this stage since we need both stack & task indices for that
 		taskDetails:    details.NewBox(ctx.Screen),
-		data:           stacks,
+		data:           entities.NewStackTree(stacks).Ordered(nil),
+		stacks:         stacks,
+		collapsed:      make(map[string]bool),
 		help:           help.NewModel(keys.StackMappings),
 		navigationKeys: keys.TableMappings,
 		showHelp:       true,
 		context:        ctx,
 		canFilter:      true,
+		marked:         make(map[string]bool),
 	}
 
 	if ctx.Config.Display.Finished.Since != "" {
@@ -95,20 +126,165 @@ func Initialize(ctx *state.Context) ModelWrapper {
 			m.filterSince = parsed
 		}
 	}
+	// the last session is restored (as far as the stacks/tasks still exist) on first render
+	session, err := state.LoadSession(ctx.Config.SessionFile())
+	if err != nil {
+		ctx.DB.Log("session", err)
+	}
+	m.session = session
+	m.canFilter = !session.Unfiltered
+	m.showHelp = !session.HideHelp
+	if session.StackID != "" {
+		m.prevState = preserveState{retainState: true, stackID: session.StackID, taskID: session.TaskID}
+	}
 	m.stackTable.Focus()
 	m.taskTable.Blur()
 	m.taskDetails.Blur()
 	return ModelWrapper{m}
 }
 
+// saveSession will save the interactive state (for the next run)
+func (m *model) saveSession() {
+	session := state.Session{
+		Unfiltered: !m.canFilter,
+		HideHelp:   !m.showHelp,
+		Focus:      m.preInputFocus,
+	}
+	switch {
+	case m.stackTable.Focused():
+		session.Focus = stackViewName
+	case m.taskTable.Focused():
+		session.Focus = taskViewName
+	case m.taskDetails.Focused():
+		session.Focus = detailViewName
+	}
+	if len(m.data) > 0 {
+		stack := m.data[m.stackTable.Cursor()]
+		session.StackID = stack.ID
+		if len(stack.Tasks) > 0 && len(m.taskTable.Rows()) > 0 {
+			session.TaskID = stack.Tasks[m.taskTable.Cursor()].ID
+			session.Offset = m.taskDetails.ViewPort.YOffset
+			session.FocusIndex = m.taskDetails.FocusIndex
+		}
+	}
+	m.context.DB.Log("session", session.Save(m.context.Config.SessionFile()))
+}
+
+// restoreSession will focus the pane (and details position) of the last session,
+// anything no longer found (e.g. a deleted task) is left as is
+func (m *model) restoreSession() {
+	session := m.session
+	m.session = state.Session{}
+	if len(m.data) == 0 || session.Focus == "" || session.Focus == stackViewName {
+		return
+	}
+	stack := m.data[m.stackTable.Cursor()]
+	if stack.ID != session.StackID || len(m.taskTable.Rows()) == 0 {
+		return
+	}
+	m.showTasks = true
+	m.focusTasks()
+	task := stack.Tasks[m.taskTable.Cursor()]
+	if task.ID != session.TaskID {
+		return
+	}
+	if session.FocusIndex >= 0 && session.FocusIndex <= definitions.TaskLastIndex {
+		m.taskDetails.FocusIndex = session.FocusIndex
+	}
+	m.taskDetails.ViewPort.YOffset = session.Offset
+	m.updateDetailsBoxData(true)
+	if session.Focus == detailViewName {
+		m.showDetails = true
+		m.focusDetails()
+	}
+}
+
+// switchProfile will close the current profile and reload the model for another profile
+func (m *model) switchProfile(profile string) tea.Cmd {
+	m.saveSession()
+	ctx, err := m.context.Switch(profile)
+	if ctx == nil {
+		// nothing is open (the previous profile could not be reopened either)
+		return tea.Quit
+	}
+	var cmd tea.Cmd
+	if ctx != m.context {
+		// the new profile (or the previous one, reopened when the new one failed to start)
+		width, height := m.context.Screen.Width, m.context.Screen.Height
+		generation := m.tickGeneration + 1
+		*m = *Initialize(ctx).Backing
+		m.firstRender = true
+		m.tickGeneration = generation
+		cmd = tea.Batch(func() tea.Msg {
+			return tea.WindowSizeMsg{Width: width, Height: height}
+		}, m.startTimerTick(), m.watchDay())
+	}
+	if err != nil {
+		m.context.DB.Log("profile", err)
+		m.notice = fmt.Sprintf("switching to '%s' failed: %v", profile, err)
+	}
+	return cmd
+}
+
 // Init initializes the model
 func (m *model) Init() tea.Cmd {
 	m.firstRender = true
-	return nil
+	return tea.Batch(m.startTimerTick(), m.watchDay())
+}
+
+// Tick (once a minute, to update the footer) while a timer is running, unless a tick is already scheduled
+func (m *model) startTimerTick() tea.Cmd {
+	if m.ticking {
+		return nil
+	}
+	if _, ok := m.runningTask(); !ok {
+		return nil
+	}
+	m.ticking = true
+	generation := m.tickGeneration
+	return tea.Every(time.Minute, func(time.Time) tea.Msg {
+		return timerTick(generation)
+	})
 }
 
 // Update will update the model
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if tick, ok := msg.(timerTick); ok {
+		if int(tick) != m.tickGeneration {
+			return m, nil
+		}
+		m.ticking = false
+		return m, m.startTimerTick()
+	}
+	if tick, ok := msg.(dayTick); ok {
+		if int(tick) != m.tickGeneration {
+			return m, nil
+		}
+		if m.firstRender || m.showInput || m.showCustomInput || m.showScreen {
+			// offered once nothing else is shown
+			return m, m.dayTickIn(time.Minute)
+		}
+		m.checkRollOver()
+		return m, m.watchDay()
+	}
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.notice = ""
+	}
+	// Transfer control to the (help/stats) screen until it is closed
+	if m.showScreen {
+		switch msg := msg.(type) {
+		case messages.Main:
+			m.closeScreen(msg.Value)
+			return m, nil
+		case tea.WindowSizeMsg:
+			m.context.Screen.Width = msg.Width
+			m.context.Screen.Height = msg.Height
+			m.updateViewDimensions(tablesOffset)
+		}
+		var cmd tea.Cmd
+		m.screen, cmd = m.screen.Update(msg)
+		return m, cmd
+	}
 	// Transfer control to inputForm's Update method
 	if m.showInput {
 		switch msg := msg.(type) {
@@ -138,14 +314,14 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.navigationKeys = keys.DetailsMappings
 			}
 
-			m.updateViewDimensions(10)
+			m.updateViewDimensions(tablesOffset)
 
 			return m, nil
 
 		case tea.WindowSizeMsg:
 			m.context.Screen.Width = msg.Width
 			m.context.Screen.Height = msg.Height
-			m.updateViewDimensions(14)
+			m.updateViewDimensions(m.inputOffset())
 			return m, nil
 
 		default:
@@ -200,6 +376,14 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, nil
 
 					case taskViewName:
+						if len(m.marked) > 0 {
+							m.bulkUpdate(func(t entities.Task) {
+								t.Delete(m.context.DB)
+							})
+							m.taskTable.SetCursor(0)
+							m.refreshData()
+							return m, nil
+						}
 						stackIndex := m.stackTable.Cursor()
 						taskIndex := m.taskTable.Cursor()
 
@@ -240,6 +424,18 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				newStackID := response.Key
+				if len(m.marked) > 0 {
+					m.bulkUpdate(func(t entities.Task) {
+						if t.StackID == newStackID {
+							return
+						}
+						t.StackID = newStackID
+						t.Save(m.context.DB)
+					})
+					m.taskTable.SetCursor(0)
+					m.refreshData()
+					return m, nil
+				}
 
 				stackIndex := m.stackTable.Cursor()
 				taskIndex := m.taskTable.Cursor()
@@ -264,6 +460,309 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				t, _ := inp.(lists.Selector)
 				m.customInput = t
 
+				return m, cmd
+			}
+
+		case definitions.IsNest:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.stackTable.Focus()
+				m.help = help.NewModel(keys.StackMappings)
+
+				response := msg.Value.(definitions.KeyValue)
+
+				if response.Value == "" {
+					return m, nil
+				}
+
+				stack := m.data[m.stackTable.Cursor()]
+				if stack.ParentID == response.Key {
+					return m, nil
+				}
+				stack.ParentID = response.Key
+				stack.Ordinal = 0
+				stack.Save(m.context.DB)
+
+				// keep the stack visible under its new parent
+				delete(m.collapsed, response.Key)
+				m.preserveState()
+				m.refreshData()
+				return m, nil
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(lists.Selector)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		case definitions.IsCommand:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.focusPreInput()
+
+				command, ok := msg.Value.(palette.Command)
+				if !ok {
+					return m, nil
+				}
+				return m.runCommand(command)
+
+			default:
+				var cmd tea.Cmd
+				m.customInput, cmd = m.customInput.Update(msg)
+				return m, cmd
+			}
+
+		case definitions.IsProfile:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.stackTable.Focus()
+				m.help = help.NewModel(keys.StackMappings)
+
+				response := msg.Value.(definitions.KeyValue)
+				if response.Value == "" || response.Key == m.context.Config.Profile {
+					return m, nil
+				}
+				return m, m.switchProfile(response.Key)
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(lists.Selector)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		case definitions.IsRollOver:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.focusPreInput()
+				if response := msg.Value.(definitions.KeyValue); response.Value != "" {
+					m.rollOver(response.Key)
+				}
+				return m, nil
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(lists.Selector)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		case definitions.IsClone, definitions.IsCloneStack:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				response := msg.Value.(definitions.KeyValue)
+				if m.customInputType == definitions.IsClone {
+					m.taskTable.Focus()
+					m.help = help.NewModel(keys.TaskMappings)
+					if response.Value != "" {
+						m.cloneTasks(response.Key)
+					}
+					return m, nil
+				}
+				m.stackTable.Focus()
+				m.help = help.NewModel(keys.StackMappings)
+				if response.Value == "" {
+					return m, nil
+				}
+				m.cloneOptions.ResetFinished = response.Key == "reset"
+				return m, m.showCloneShift()
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(lists.Selector)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		case definitions.IsCloneShift:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				var shift time.Duration
+				if value := strings.TrimSpace(msg.Value.(string)); value != "" {
+					offset, err := durations.Parse(value)
+					if err != nil {
+						// the input is kept open to correct the offset
+						m.notice = fmt.Sprintf("invalid offset: %s", value)
+						return m, nil
+					}
+					shift = offset
+				}
+				m.showCustomInput = false
+				m.focusPreInput()
+				m.cloneStack(shift)
+				return m, nil
+
+			case tea.KeyMsg:
+				switch {
+				case key.Matches(msg, keys.Mappings.Return):
+					m.showCustomInput = false
+					m.focusPreInput()
+					return m, nil
+				case key.Matches(msg, keys.Mappings.Exit):
+					return m, tea.Quit
+				}
+			}
+			var cmd tea.Cmd
+			m.customInput, cmd = m.customInput.Update(msg)
+			return m, cmd
+
+		case definitions.IsTemplate:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.focusPreInput()
+
+				response := msg.Value.(definitions.KeyValue)
+				if response.Value != "" {
+					m.applyTemplate(response.Key)
+				}
+				return m, nil
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(lists.Selector)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		case definitions.IsSaveTemplate:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.focusPreInput()
+
+				if name := strings.TrimSpace(msg.Value.(string)); name != "" {
+					m.saveTemplate(name)
+				}
+				return m, nil
+
+			case tea.KeyMsg:
+				switch {
+				case key.Matches(msg, keys.Mappings.Return):
+					return m, messages.MainGoTo
+				case key.Matches(msg, keys.Mappings.Exit):
+					return m, tea.Quit
+				}
+			}
+			var cmd tea.Cmd
+			m.customInput, cmd = m.customInput.Update(msg)
+			return m, cmd
+
+		// Transfer control to bulk toggle confirmation model
+		case definitions.IsToggle:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.taskTable.Focus()
+				m.help = help.NewModel(keys.TaskMappings)
+
+				if msg.Value.(string) == "y" {
+					now := time.Now()
+					m.bulkUpdate(func(t entities.Task) {
+						t.ToggleFinished(m.context.DB, now)
+					})
+					m.preserveState()
+					m.refreshData()
+				}
+				return m, nil
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(deletion.Confirmation)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		case definitions.IsPriority, definitions.IsShift:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.taskTable.Focus()
+				m.help = help.NewModel(keys.TaskMappings)
+
+				response := msg.Value.(definitions.KeyValue)
+				if response.Value == "" {
+					return m, nil
+				}
+
+				if m.customInputType == definitions.IsPriority {
+					priority, err := strconv.ParseUint(response.Key, 10, 64)
+					if err != nil {
+						return m, nil
+					}
+					m.bulkUpdate(func(t entities.Task) {
+						t.Priority = priority
+						t.Save(m.context.DB)
+					})
+				} else {
+					offset, err := time.ParseDuration(response.Key)
+					if err != nil {
+						return m, nil
+					}
+					m.bulkUpdate(func(t entities.Task) {
+						if t.Deadline.IsZero() {
+							return
+						}
+						t.Deadline = t.Deadline.Add(offset)
+						t.Save(m.context.DB)
+					})
+				}
+				m.preserveState()
+				m.refreshData()
+				return m, nil
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				t, _ := inp.(lists.Selector)
+				m.customInput = t
+
+				return m, cmd
+			}
+
+		// Transfer control to the trash/archive views
+		case definitions.IsTrash, definitions.IsArchive:
+			switch msg := msg.(type) {
+
+			case messages.Main:
+				m.showCustomInput = false
+				m.focusPreInput()
+
+				if msg.Value.(string) == "refresh" {
+					m.clearMarks()
+					m.preserveState()
+					m.refreshData()
+				}
+				return m, nil
+
+			default:
+				inp, cmd := m.customInput.Update(msg)
+				m.customInput = inp
+				if t, ok := inp.(trash.Model); ok {
+					m.help = help.NewModel(t.HelpKeys())
+				}
+
 				return m, cmd
 			}
 		}
@@ -279,27 +778,12 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Mappings.Left):
 			if m.stackTable.Focused() {
 				if m.showDetails {
-					m.stackTable.Blur()
-					m.taskTable.Blur()
-					m.taskDetails.Focus()
-					m.help = help.NewModel(keys.TaskDetailsMappings)
-					m.navigationKeys = keys.DetailsMappings
-
+					m.focusDetails()
 				}
 			} else if m.taskTable.Focused() {
-				m.stackTable.Focus()
-				m.taskTable.Blur()
-				m.taskDetails.Blur()
-				m.help = help.NewModel(keys.StackMappings)
-				m.navigationKeys = keys.TableMappings
-
+				m.focusStacks()
 			} else if m.taskDetails.Focused() {
-				m.stackTable.Blur()
-				m.taskTable.Focus()
-				m.taskDetails.Blur()
-				m.help = help.NewModel(keys.TaskMappings)
-				m.navigationKeys = keys.TableMappings
-
+				m.focusTasks()
 			}
 			return m, nil
 
@@ -307,29 +791,17 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.stackTable.Focused() {
 				if len(m.stackTable.Rows()) > 0 {
 					m.showTasks = true
-					m.stackTable.Blur()
-					m.taskTable.Focus()
-					m.taskDetails.Blur()
-					m.help = help.NewModel(keys.TaskMappings)
-					m.navigationKeys = keys.TableMappings
+					m.focusTasks()
 					return m, nil
 				}
 			} else if m.taskTable.Focused() {
 				if len(m.taskTable.Rows()) > 0 {
 					m.showDetails = true
-					m.stackTable.Blur()
-					m.taskTable.Blur()
-					m.taskDetails.Focus()
-					m.help = help.NewModel(keys.TaskDetailsMappings)
-					m.navigationKeys = keys.DetailsMappings
+					m.focusDetails()
 					return m, nil
 				}
 			} else if m.taskDetails.Focused() {
-				m.stackTable.Focus()
-				m.taskTable.Blur()
-				m.taskDetails.Blur()
-				m.help = help.NewModel(keys.StackMappings)
-				m.navigationKeys = keys.TableMappings
+				m.focusStacks()
 				return m, nil
 			}
 
@@ -344,6 +816,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Mappings.Up):
 			if m.stackTable.Focused() {
 				m.stackTable.MoveUp(1)
+				m.clearMarks()
 				m.taskTable.SetCursor(0)
 				m.taskDetails.FocusIndex = 0
 				m.showTasks = false
@@ -368,6 +841,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Mappings.Down):
 			if m.stackTable.Focused() {
 				m.stackTable.MoveDown(1)
+				m.clearMarks()
 				m.taskTable.SetCursor(0)
 				m.taskDetails.FocusIndex = 0
 				m.showTasks = false
@@ -400,7 +874,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.preInputFocus = taskViewName
 				newTask := entities.NewTask()
 				newTask.StackID = m.data[m.stackTable.Cursor()].ID
-				m.input = inputs.NewTaskForm(newTask, 0, m.context)
+				m.input = inputs.NewFullTaskForm(newTask, m.context)
 
 			} else if m.taskDetails.Focused() {
 				return m, nil
@@ -410,7 +884,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.taskTable.Blur()
 			m.taskDetails.Blur()
 
-			m.updateViewDimensions(14)
+			m.updateViewDimensions(m.inputOffset())
 
 			m.showInput = true
 
@@ -434,6 +908,9 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			} else if m.taskDetails.Focused() {
+				if m.taskDetails.FocusIndex == definitions.TaskHistoryIndex {
+					return m, nil
+				}
 				m.preInputFocus = detailViewName
 				m.input = inputs.NewTaskForm(m.data[m.stackTable.Cursor()].Tasks[m.taskTable.Cursor()], m.taskDetails.FocusIndex, m.context)
 			}
@@ -442,7 +919,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.taskTable.Blur()
 			m.taskDetails.Blur()
 
-			m.updateViewDimensions(14)
+			m.updateViewDimensions(m.inputOffset())
 
 			m.showInput = true
 
@@ -456,6 +933,17 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showCustomInput = true
 				m.customInputType = definitions.IsDelete
 				m.customInput = deletion.NewConfirmation()
+				stack := m.data[m.stackTable.Cursor()]
+				count := len(stack.Tasks)
+				nested := entities.NewStackTree(m.stacks).Descendants(stack.ID)
+				for _, s := range nested {
+					count += len(s.Tasks)
+				}
+				if len(nested) > 0 {
+					m.customInput = deletion.NewPromptConfirmation(definitions.IsDelete, fmt.Sprintf("Move stack, its %d nested stack(s) and %d task(s) to the trash?", len(nested), count))
+				} else if count > 0 {
+					m.customInput = deletion.NewPromptConfirmation(definitions.IsDelete, fmt.Sprintf("Move stack and its %d task(s) to the trash?", count))
+				}
 				m.stackTable.Blur()
 				m.help = help.Model{}
 
@@ -469,6 +957,9 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.showCustomInput = true
 					m.customInputType = definitions.IsDelete
 					m.customInput = deletion.NewConfirmation()
+					if len(m.marked) > 0 {
+						m.customInput = deletion.NewPromptConfirmation(definitions.IsDelete, fmt.Sprintf("Delete %d marked task(s)?", len(m.marked)))
+					}
 					m.taskTable.Blur()
 					m.help = help.Model{}
 
@@ -479,38 +970,100 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Mappings.Toggle):
 			// Toggle task finish status
 			if m.taskTable.Focused() {
+				if len(m.marked) > 0 {
+					m.showBulkInput(definitions.IsToggle, deletion.NewPromptConfirmation(definitions.IsToggle, fmt.Sprintf("Toggle %d marked task(s)?", len(m.marked))), help.Model{})
+					return m, nil
+				}
 				stackIndex := m.stackTable.Cursor()
 				taskIndex := m.taskTable.Cursor()
 
-				var currTask entities.Task
 				if len(m.data[stackIndex].Tasks) > 0 {
-					stack := m.data[stackIndex]
-					currTask = stack.Tasks[taskIndex]
-
-					if currTask.Finished.IsZero() {
-						currTask.Finished = time.Now()
-					} else {
-						currTask.Finished = time.Time{}
-					}
-					currTask.Save(m.context.DB)
-					stack.Save(m.context.DB)
-
-					stack.Tasks[taskIndex] = currTask
-					m.data[stackIndex] = stack
+					m.data[stackIndex].Tasks[taskIndex].ToggleFinished(m.context.DB, time.Now())
 
 					// Changing finish status will lead to reordering, so state has to be preserved
 					m.preserveState()
-					m.updateSelectionData(stackDataCategory)
+					m.refreshData()
 					return m, nil
 				}
 			}
+		case key.Matches(msg, keys.Mappings.Timer):
+			if m.taskTable.Focused() && len(m.taskTable.Rows()) > 0 {
+				task := m.data[m.stackTable.Cursor()].Tasks[m.taskTable.Cursor()]
+				entities.ToggleTimer(m.context.DB, task, time.Now())
+				m.preserveState()
+				m.refreshData()
+				return m, m.startTimerTick()
+			}
+		case key.Matches(msg, keys.Mappings.Sort):
+			if m.taskTable.Focused() {
+				stack := m.data[m.stackTable.Cursor()]
+				stack.Sort = stack.Sort.Next()
+				stack.Save(m.context.DB)
+				m.preserveState()
+				m.refreshData()
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.MoveUp, keys.Mappings.MoveDown):
+			delta := 1
+			if key.Matches(msg, keys.Mappings.MoveUp) {
+				delta = -1
+			}
+			// state is preserved before moving as the (sorted) data is reordered in place
+			if m.stackTable.Focused() && len(m.data) > 1 {
+				// stacks only move among their siblings
+				stack := m.data[m.stackTable.Cursor()]
+				siblings := entities.NewStackTree(m.stacks).Children(stack.ParentID)
+				m.preserveState()
+				if entities.MoveStack(m.context.DB, siblings, entities.FindByIndex(siblings, stack.ID), delta) {
+					m.refreshData()
+				}
+				return m, nil
+			} else if m.taskTable.Focused() && len(m.taskTable.Rows()) > 1 {
+				stack := m.data[m.stackTable.Cursor()]
+				m.preserveState()
+				if entities.MoveTask(m.context.DB, stack, stack.Tasks, m.taskTable.Cursor(), delta) {
+					m.refreshData()
+				}
+				return m, nil
+			}
 		case key.Matches(msg, keys.Mappings.Filters):
 			if m.taskTable.Focused() {
 				m.canFilter = !m.canFilter
 				m.updateSelectionData(stackDataCategory)
 				return m, nil
 			}
+		case key.Matches(msg, keys.Mappings.Collapse):
+			if m.stackTable.Focused() {
+				stack := m.data[m.stackTable.Cursor()]
+				if !entities.NewStackTree(m.stacks).HasChildren(stack.ID) {
+					return m, nil
+				}
+				if m.collapsed[stack.ID] {
+					delete(m.collapsed, stack.ID)
+				} else {
+					m.collapsed[stack.ID] = true
+				}
+				m.updateSelectionData(stackDataCategory)
+				return m, nil
+			}
 		case key.Matches(msg, keys.Mappings.Move):
+			if m.stackTable.Focused() {
+				stack := m.data[m.stackTable.Cursor()]
+				tree := entities.NewStackTree(m.stacks)
+				// a stack can't be nested under itself (or anything nested under it)
+				exclude := map[string]bool{stack.ID: true}
+				for _, s := range tree.Descendants(stack.ID) {
+					exclude[s.ID] = true
+				}
+				opts := append([]definitions.KeyValue{{Key: "", Value: "(top level)"}}, m.stackOptions(exclude)...)
+				m.preInputFocus = stackViewName
+				m.showCustomInput = true
+				m.customInputType = definitions.IsNest
+				m.stackTable.Blur()
+				m.customInput = lists.NewSelector(opts, "", messages.MainGoToWith)
+				m.help = help.NewModel(keys.ListSelectorMappings)
+				return m, nil
+			}
 			if m.taskTable.Focused() {
 				stackIndex := m.stackTable.Cursor()
 
@@ -520,38 +1073,164 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.customInputType = definitions.IsMove
 					m.taskTable.Blur()
 
-					opts := []definitions.KeyValue{}
-					for _, stack := range m.data {
-						entry := definitions.KeyValue{
-							Key:   stack.ID,
-							Value: stack.Title,
-						}
-						opts = append(opts, entry)
-					}
-					m.customInput = lists.NewSelector(opts, "", messages.MainGoToWith)
+					m.customInput = lists.NewSelector(m.stackOptions(nil), "", messages.MainGoToWith)
 
 					m.help = help.NewModel(keys.ListSelectorMappings)
 					return m, nil
 				}
 			}
+		case key.Matches(msg, keys.Mappings.Mark):
+			if m.taskTable.Focused() && len(m.taskTable.Rows()) > 0 {
+				m.toggleMark(m.taskTable.Cursor())
+				m.taskTable.MoveDown(1)
+				m.taskDetails.FocusIndex = 0
+				m.showDetails = false
+				m.updateSelectionData(taskDataCategory)
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.MarkAll, keys.Mappings.Invert):
+			if m.taskTable.Focused() {
+				invert := key.Matches(msg, keys.Mappings.Invert)
+				for idx := range m.taskTable.Rows() {
+					if invert {
+						m.toggleMark(idx)
+					} else {
+						m.marked[m.data[m.stackTable.Cursor()].Tasks[idx].ID] = true
+					}
+				}
+				m.updateSelectionData(taskDataCategory)
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.Priority):
+			if m.taskTable.Focused() && len(m.taskTable.Rows()) > 0 {
+				m.showBulkInput(definitions.IsPriority, lists.NewSelector(inputs.PriorityOptions(m.context.Screen.Theme), "", messages.MainGoToWith), help.NewModel(keys.ListSelectorMappings))
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.Shift):
+			if m.taskTable.Focused() && len(m.taskTable.Rows()) > 0 {
+				m.showBulkInput(definitions.IsShift, lists.NewSelector(shiftOptions, "", messages.MainGoToWith), help.NewModel(keys.ListSelectorMappings))
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.Trash):
+			if m.stackTable.Focused() || m.taskTable.Focused() {
+				m.showView(definitions.IsTrash, trash.New(m.context), keys.TrashMappings)
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.Archives):
+			if m.stackTable.Focused() || m.taskTable.Focused() {
+				a, err := archive.New(m.context)
+				if err != nil {
+					m.context.DB.Log("archive", err)
+					return m, nil
+				}
+				m.showView(definitions.IsArchive, a, keys.ArchiveMappings)
+				return m, nil
+			}
+		case key.Matches(msg, keys.Mappings.Archive):
+			if m.stackTable.Focused() || m.taskTable.Focused() {
+				store, err := m.context.Archive()
+				if err != nil {
+					m.context.DB.Log("archive", err)
+					return m, nil
+				}
+				if m.stackTable.Focused() {
+					stackIndex := m.stackTable.Cursor()
+					if stackIndex == len(m.stackTable.Rows())-1 {
+						m.stackTable.SetCursor(stackIndex - 1)
+					}
+					entities.ArchiveStack(m.context.DB, store, m.data[stackIndex])
+					m.showTasks = false
+					m.showDetails = false
+				} else {
+					entities.ArchiveTasks(m.context.DB, store, m.selectedTasks())
+					m.clearMarks()
+					m.taskTable.SetCursor(0)
+				}
+				m.refreshData()
+				return m, nil
+			}
 		case key.Matches(msg, keys.Mappings.Help):
+			context, _, _ := m.focusContext()
+			m.screen = help.NewScreen(context, m.context.Config.Paths(), m.context.Screen.Width, m.context.Screen.Height)
+			m.showScreen = true
+			return m, nil
+
+		case key.Matches(msg, keys.Mappings.Stats):
+			m.screen = stats.NewScreen(m.stacks, m.context.Screen.Width, m.context.Screen.Height)
+			m.showScreen = true
+			return m, nil
+
+		case key.Matches(msg, keys.Mappings.Focus):
+			m.showFocus()
+			return m, nil
+
+		case key.Matches(msg, keys.Mappings.Plan):
+			if m.taskTable.Focused() && len(m.taskTable.Rows()) > 0 {
+				m.planTasks()
+				return m, nil
+			}
+
+		case key.Matches(msg, keys.Mappings.Hints):
 			m.showHelp = !m.showHelp
 			return m, nil
 
+		case key.Matches(msg, keys.Mappings.Command):
+			return m, m.showPalette()
+
+		case key.Matches(msg, keys.Mappings.Clone):
+			if m.stackTable.Focused() && len(m.stackTable.Rows()) > 0 {
+				return m, m.showCloneStack()
+			}
+			if m.taskTable.Focused() && len(m.taskTable.Rows()) > 0 {
+				return m, m.showCloneTasks()
+			}
+
+		case key.Matches(msg, keys.Mappings.Template):
+			if m.stackTable.Focused() || m.taskTable.Focused() {
+				return m, m.showTemplates()
+			}
+
+		case key.Matches(msg, keys.Mappings.SaveAs):
+			if (m.stackTable.Focused() && len(m.stackTable.Rows()) > 0) || (m.taskTable.Focused() && len(m.taskTable.Rows()) > 0) {
+				return m, m.showSaveTemplate()
+			}
+
+		case key.Matches(msg, keys.Mappings.Profile):
+			names := m.context.Config.ProfileNames()
+			if m.stackTable.Focused() && m.context.Switch != nil && len(names) > 0 {
+				opts := []definitions.KeyValue{{Key: "", Value: "(default)"}}
+				for _, name := range names {
+					opts = append(opts, definitions.KeyValue{Key: name, Value: name})
+				}
+				m.preInputFocus = stackViewName
+				m.showCustomInput = true
+				m.customInputType = definitions.IsProfile
+				m.stackTable.Blur()
+				m.customInput = lists.NewSelector(opts, "", messages.MainGoToWith)
+				m.help = help.NewModel(keys.ListSelectorMappings)
+				return m, nil
+			}
+
 		case key.Matches(msg, keys.Mappings.Quit, keys.Mappings.Exit):
+			m.saveSession()
 			return m, tea.Quit
 		}
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.context.Screen.Width = msg.Width
 		m.context.Screen.Height = msg.Height
-		m.updateViewDimensions(10)
+		m.updateViewDimensions(tablesOffset)
 
 		if m.firstRender {
 			// updateSelectionData() is called here instead of being called from Init()
 			// since details box rendering requires screen dimensions, which aren't set at the time of Init()
 			m.updateSelectionData(stackDataCategory)
+			m.restoreSession()
 			m.firstRender = false
+			m.checkRollOver()
 		}
 	}
 
@@ -560,6 +1239,9 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View handles model view
 func (m *model) View() string {
+	if m.showScreen {
+		return m.screen.View()
+	}
 	var stackView, taskView, detailView string
 
 	if m.stackTable.Focused() {
@@ -594,6 +1276,7 @@ func (m *model) View() string {
 	}
 
 	tablesView := lipgloss.JoinHorizontal(lipgloss.Center, viewArr...)
+	m.context.Screen.Layout(viewArr...)
 
 	if m.showCustomInput {
 		tablesView = lipgloss.JoinVertical(lipgloss.Left,
@@ -611,12 +1294,15 @@ func (m *model) View() string {
 		m.help = help.NewModel(m.input.HelpKeys())
 	}
 
-	var errorsText string
+	var status []string
 	if m.context.DB.Errored() {
-		errorsText = "[errors logged]"
+		status = append(status, "[errors logged]")
+	}
+	if m.notice != "" {
+		status = append(status, m.notice)
 	}
 
-	tablesView = lipgloss.JoinVertical(lipgloss.Left, tablesView, errorsText)
+	tablesView = lipgloss.JoinVertical(lipgloss.Left, tablesView, strings.Join(status, " "))
 	if m.showHelp {
 		if !m.showInput && !m.showCustomInput {
 			navigationHelp := help.NewModel(m.navigationKeys)
@@ -629,20 +1315,34 @@ func (m *model) View() string {
 
 func (m *model) stackView() string {
 	m.stackTable.SetHeight(m.context.Screen.Table.ViewHeight)
-	return lipgloss.JoinVertical(lipgloss.Center, m.stackTable.View(), m.stackFooter())
+	overdue := m.context.Screen.Theme.Overdue
+	view := tables.Render(m.stackTable, display.TableStyle(display.StackTableType), &m.stackViewport, func(_, col int) lipgloss.Style {
+		if col == len(tables.StackColumns)-1 {
+			return overdue
+		}
+		return lipgloss.NewStyle()
+	})
+	return lipgloss.JoinVertical(lipgloss.Center, view, m.stackFooter())
 }
 
 func (m *model) stackFooter() string {
 	stackFooterStyle := display.FooterContainerStyle.Width(display.StackTableWidth)
 
-	info := display.FooterInfoStyle.Render(fmt.Sprintf("%d/%d", m.stackTable.Cursor()+1, len(m.stackTable.Rows())))
+	text := fmt.Sprintf("%d/%d", m.stackTable.Cursor()+1, len(m.stackTable.Rows()))
+	if profile := m.context.Config.Profile; profile != "" {
+		text = fmt.Sprintf("%s · %s", text, profile)
+	}
+	info := display.FooterInfoStyle.Render(text)
 
 	return stackFooterStyle.Render(info)
 }
 
 func (m *model) taskView() string {
 	m.taskTable.SetHeight(m.context.Screen.Table.ViewHeight)
-	return lipgloss.JoinVertical(lipgloss.Center, m.taskTable.View(), m.taskFooter())
+	view := tables.Render(m.taskTable, display.TableStyle(display.TaskTableType), &m.taskViewport, func(row, _ int) lipgloss.Style {
+		return m.taskStyles[row]
+	})
+	return lipgloss.JoinVertical(lipgloss.Center, view, m.taskFooter())
 }
 
 func (m *model) taskFooter() string {
@@ -651,14 +1351,84 @@ func (m *model) taskFooter() string {
 	if len(m.taskTable.Rows()) == 0 {
 		return taskFooterStyle.Render("Press 'n' to create a new task")
 	}
-	info := display.FooterInfoStyle.Render(fmt.Sprintf("%d/%d", m.taskTable.Cursor()+1, len(m.taskTable.Rows())))
+	text := fmt.Sprintf("%d/%d", m.taskTable.Cursor()+1, len(m.taskTable.Rows()))
+	if len(m.marked) > 0 {
+		text = fmt.Sprintf("%s (%d marked)", text, len(m.marked))
+	}
+	if strategy := m.data[m.stackTable.Cursor()].Sort; strategy != entities.SortDefault {
+		text = fmt.Sprintf("%s (by %s)", text, strategy)
+	}
+	info := display.FooterInfoStyle.Render(text)
+	if running, ok := m.runningTask(); ok {
+		title := []rune(running.Title)
+		if len(title) > 15 {
+			title = append(title[:14], '…')
+		}
+		timer := fmt.Sprintf("⏱ %s %s", string(title), durations.Format(running.Tracked(time.Time{}, time.Now())))
+		info = lipgloss.JoinHorizontal(lipgloss.Center, info, " ", display.FooterInfoStyle.Render(timer))
+	}
 	return taskFooterStyle.Render(info)
 }
 
+// Options to select a stack (in tree order, indented by depth)
+func (m *model) stackOptions(exclude map[string]bool) []definitions.KeyValue {
+	tree := entities.NewStackTree(m.stacks)
+	var opts []definitions.KeyValue
+	for _, stack := range tree.Ordered(nil) {
+		if exclude[stack.ID] {
+			continue
+		}
+		opts = append(opts, definitions.KeyValue{
+			Key:   stack.ID,
+			Value: strings.Repeat("  ", tree.Depth(stack.ID)) + stack.Title,
+		})
+	}
+	return opts
+}
+
+func (m *model) runningTask() (entities.Task, bool) {
+	for _, stack := range m.stacks {
+		for _, task := range stack.Tasks {
+			if task.Running() {
+				return task, true
+			}
+		}
+	}
+	return entities.Task{}, false
+}
+
+// Show a (non-input) view below the tables, e.g. trash or archive
+func (m *model) showView(inputType string, view tea.Model, helpKeys keys.Map) {
+	m.preInputFocus = stackViewName
+	if m.taskTable.Focused() {
+		m.preInputFocus = taskViewName
+	}
+	m.showCustomInput = true
+	m.customInputType = inputType
+	m.customInput = view
+	m.stackTable.Blur()
+	m.taskTable.Blur()
+	m.help = help.NewModel(helpKeys)
+}
+
+// Return focus to the table that was focused before a custom input was shown
+func (m *model) focusPreInput() {
+	switch m.preInputFocus {
+	case stackViewName:
+		m.stackTable.Focus()
+		m.help = help.NewModel(keys.StackMappings)
+	case taskViewName:
+		m.taskTable.Focus()
+		m.help = help.NewModel(keys.TaskMappings)
+	case detailViewName:
+		m.taskDetails.Focus()
+		m.help = help.NewModel(keys.TaskDetailsMappings)
+	}
+}
+
 // Pull new data from database
 func (m *model) refreshData() {
-	stacks := entities.FetchStacks(m.context.DB)
-	m.data = stacks
+	m.stacks = entities.FetchStacks(m.context.DB)
 	m.updateSelectionData(stackDataCategory)
 }
 
@@ -688,9 +1458,13 @@ func (m *model) updateSelectionData(category dataCategory) {
 }
 
 func (m *model) updateStackTableData(retainIndex bool) {
-	// Set stack view data
-	// We pass a slice to stackRows, so the changes (like sorting) that happen there will be reflected in original slice
-	m.stackTable.SetRows(tables.StackRows(m.data))
+	// Set stack view data, only the stacks shown (not under a collapsed stack) are kept
+	rows, shown := tables.StackRows(m.stacks, m.collapsed, time.Now())
+	m.data = shown
+	m.stackTable.SetRows(rows)
+	if m.stackTable.Cursor() >= len(rows) {
+		m.stackTable.SetCursor(len(rows) - 1)
+	}
 
 	if retainIndex {
 		newIndex := entities.FindByIndex(m.data, m.prevState.stackID)
@@ -711,7 +1485,9 @@ func (m *model) updateTaskTableData(retainIndex bool) {
 	if m.canFilter {
 		filter = time.Now().Add(-m.filterSince)
 	}
-	m.taskTable.SetRows(tables.TaskRows(currStack.Tasks, filter))
+	rows, styles := tables.TaskRows(currStack.Tasks, currStack.Sort, filter, m.marked, m.context.Screen.Theme, time.Now())
+	m.taskTable.SetRows(rows)
+	m.taskStyles = styles
 
 	if retainIndex {
 		newIndex := entities.FindByIndex(m.data[stackIndex].Tasks, m.prevState.taskID)
@@ -752,6 +1528,38 @@ func (m *model) preserveState() {
 	}
 }
 
+// The focus helpers move focus between the panes (the help follows the focused pane)
+func (m *model) focusStacks() {
+	m.stackTable.Focus()
+	m.taskTable.Blur()
+	m.taskDetails.Blur()
+	m.help = help.NewModel(keys.StackMappings)
+	m.navigationKeys = keys.TableMappings
+}
+
+func (m *model) focusTasks() {
+	m.stackTable.Blur()
+	m.taskTable.Focus()
+	m.taskDetails.Blur()
+	m.help = help.NewModel(keys.TaskMappings)
+	m.navigationKeys = keys.TableMappings
+}
+
+func (m *model) focusDetails() {
+	m.stackTable.Blur()
+	m.taskTable.Blur()
+	m.taskDetails.Focus()
+	m.help = help.NewModel(keys.TaskDetailsMappings)
+	m.navigationKeys = keys.DetailsMappings
+}
+
+// inputOffset is the height taken around the tables with the input form shown (it replaces the navigation help)
+func (m *model) inputOffset() int {
+	form := m.context.Screen.InputFormStyle().Render(m.input.View())
+	navigation := help.NewModel(m.navigationKeys).View()
+	return tablesOffset + lipgloss.Height(form) - lipgloss.Height(navigation)
+}
+
 func (m *model) updateViewDimensions(offset int) {
 	m.context.Screen.Table.ViewHeight = m.context.Screen.Height - offset
 
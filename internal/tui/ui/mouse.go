@@ -0,0 +1,98 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// wheelLines is how far the details scroll for each step of the mouse wheel
+const wheelLines = 3
+
+// The panes are rendered (and so laid out) left to right
+const (
+	stackPane = iota
+	taskPane
+	detailPane
+)
+
+// handleMouse will focus/select (clicks) or scroll (wheel) the pane under the mouse
+func (m *model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	idx := m.context.Screen.PaneAt(msg.X, msg.Y)
+	if idx < 0 || len(m.stackTable.Rows()) == 0 {
+		return m, nil
+	}
+	pane := m.context.Screen.Panes[idx]
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		up := msg.Button == tea.MouseButtonWheelUp
+		delta := 1
+		if up {
+			delta = -1
+		}
+		switch idx {
+		case stackPane:
+			m.focusStacks()
+			m.selectStack(m.stackTable.Cursor() + delta)
+		case taskPane:
+			if m.showTasks {
+				m.focusTasks()
+				m.selectTask(m.taskTable.Cursor() + delta)
+			}
+		case detailPane:
+			if m.showDetails {
+				if up {
+					m.taskDetails.ViewPort.ScrollUp(wheelLines)
+				} else {
+					m.taskDetails.ViewPort.ScrollDown(wheelLines)
+				}
+			}
+		}
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch idx {
+		case stackPane:
+			m.focusStacks()
+			if row := pane.TableRow(msg.Y); row >= 0 && row < m.stackTable.Height() {
+				m.selectStack(row + m.stackViewport.Start())
+			}
+		case taskPane:
+			m.showTasks = true
+			m.focusTasks()
+			if row := pane.TableRow(msg.Y); row >= 0 && row < m.taskTable.Height() {
+				m.selectTask(row + m.taskViewport.Start())
+			}
+		case detailPane:
+			if len(m.taskTable.Rows()) == 0 {
+				return m, nil
+			}
+			m.showDetails = true
+			m.focusDetails()
+			m.taskDetails.Select(pane.Line(msg.Y))
+		}
+	}
+	return m, nil
+}
+
+// selectStack will move to a stack (as moving up/down the stack table does)
+func (m *model) selectStack(index int) {
+	if index < 0 || index >= len(m.stackTable.Rows()) || index == m.stackTable.Cursor() {
+		return
+	}
+	m.stackTable.SetCursor(index)
+	m.clearMarks()
+	m.taskTable.SetCursor(0)
+	m.taskDetails.FocusIndex = 0
+	m.showTasks = false
+	m.showDetails = false
+	m.updateSelectionData(taskDataCategory)
+}
+
+// selectTask will move to a task (as moving up/down the task table does)
+func (m *model) selectTask(index int) {
+	if index < 0 || index >= len(m.taskTable.Rows()) || index == m.taskTable.Cursor() {
+		return
+	}
+	m.taskTable.SetCursor(index)
+	m.taskDetails.FocusIndex = 0
+	m.showDetails = false
+	m.updateSelectionData(detailDataCategory)
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enckse/mayhem/internal/entities"
+	"github.com/enckse/mayhem/internal/templates"
+	"github.com/enckse/mayhem/internal/tui/definitions"
+	"github.com/enckse/mayhem/internal/tui/help"
+	"github.com/enckse/mayhem/internal/tui/inputs/lists"
+	"github.com/enckse/mayhem/internal/tui/inputs/text"
+	"github.com/enckse/mayhem/internal/tui/keys"
+	"github.com/enckse/mayhem/internal/tui/messages"
+)
+
+func (m *model) showStackInput(inputType string, input tea.Model, helpModel help.Model) tea.Cmd {
+	m.preInputFocus = stackViewName
+	m.showCustomInput = true
+	m.customInputType = inputType
+	m.customInput = input
+	m.stackTable.Blur()
+	m.help = helpModel
+	return input.Init()
+}
+
+// showTemplates will list the templates to create a stack from (stack table) or to add the tasks of to the
+// selected stack (task table)
+func (m *model) showTemplates() tea.Cmd {
+	dir := m.context.Config.TemplatesDirectory()
+	names, err := templates.List(dir)
+	if err != nil {
+		m.context.DB.Log("template", err)
+		return nil
+	}
+	if len(names) == 0 {
+		m.notice = fmt.Sprintf("no templates (see %s)", dir)
+		return nil
+	}
+	var opts []definitions.KeyValue
+	for _, name := range names {
+		opts = append(opts, definitions.KeyValue{Key: name, Value: name})
+	}
+	selector := lists.NewSelector(opts, "", messages.MainGoToWith)
+	if m.taskTable.Focused() {
+		m.showBulkInput(definitions.IsTemplate, selector, help.NewModel(keys.ListSelectorMappings))
+		return nil
+	}
+	return m.showStackInput(definitions.IsTemplate, selector, help.NewModel(keys.ListSelectorMappings))
+}
+
+// showSaveTemplate will prompt for the template name to save the selected stack (stack table) or the marked/selected
+// tasks (task table) as
+func (m *model) showSaveTemplate() tea.Cmd {
+	title := m.data[m.stackTable.Cursor()].Title
+	if m.taskTable.Focused() {
+		title = m.selectedTasks()[0].Title
+	}
+	name := strings.Join(strings.Fields(strings.ToLower(title)), "-")
+	name = strings.NewReplacer("/", "-", `\`, "-").Replace(strings.TrimLeft(name, "."))
+	input := text.New(name, "template name", 40, messages.MainGoToWith)
+	if m.taskTable.Focused() {
+		m.showBulkInput(definitions.IsSaveTemplate, input, help.NewModel(keys.TextInputMappings))
+		return input.Init()
+	}
+	return m.showStackInput(definitions.IsSaveTemplate, input, help.NewModel(keys.TextInputMappings))
+}
+
+// applyTemplate will create a new stack from a template (selecting it), from the task table the tasks of the template
+// are added to the selected stack instead
+func (m *model) applyTemplate(name string) {
+	t, err := templates.Load(m.context.Config.TemplatesDirectory(), name)
+	if err != nil {
+		m.context.DB.Log("template", err)
+		return
+	}
+	if m.preInputFocus == taskViewName {
+		tasks, err := t.Apply(m.context.DB, m.data[m.stackTable.Cursor()], time.Now())
+		if err != nil {
+			m.context.DB.Log("template", err)
+			return
+		}
+		m.preserveState()
+		if len(tasks) > 0 {
+			m.prevState.taskID = tasks[0].ID
+		}
+		m.refreshData()
+		return
+	}
+	stack := entities.NewStack(m.context.DB)
+	stack.Title = t.Stack
+	if strings.TrimSpace(stack.Title) == "" {
+		stack.Title = name
+	}
+	stack = stack.Save(m.context.DB).(entities.Stack)
+	if _, err := t.Apply(m.context.DB, stack, time.Now()); err != nil {
+		m.context.DB.Log("template", err)
+	}
+	m.preserveState()
+	m.prevState.stackID = stack.ID
+	m.prevState.taskID = ""
+	m.taskTable.SetCursor(0)
+	m.refreshData()
+}
+
+// saveTemplate will save the selected stack (and its tasks), or the marked/selected tasks, as a template
+func (m *model) saveTemplate(name string) {
+	stack := m.data[m.stackTable.Cursor()]
+	t := templates.FromStack(stack)
+	if m.preInputFocus == taskViewName {
+		t = templates.FromTasks(m.selectedTasks(), stack.Sort)
+		m.clearMarks()
+	}
+	if err := t.Save(m.context.Config.TemplatesDirectory(), name); err != nil {
+		m.context.DB.Log("template", err)
+	}
+}